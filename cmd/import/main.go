@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Run-Panel/VerTree/internal/config"
+	"github.com/Run-Panel/VerTree/internal/database"
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+)
+
+// import bulk imports historical versions of an application from a release manifest
+// (JSON/YAML) or a GitHub Releases API JSON export, e.g.
+//
+//	go run ./cmd/import -app app_abc123 -file releases.json -dry-run
+func main() {
+	appID := flag.String("app", "", "application ID to import versions into (required)")
+	file := flag.String("file", "", "path to the manifest file (required)")
+	format := flag.String("format", "", "manifest format: json, yaml or github (detected when omitted)")
+	channel := flag.String("channel", "", "default channel for versions without one")
+	dryRun := flag.Bool("dry-run", false, "validate the manifest without importing anything")
+	skipExisting := flag.Bool("skip-existing", false, "skip versions that already exist instead of failing")
+	flag.Parse()
+
+	if *appID == "" || *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatalf("Failed to read manifest: %v", err)
+	}
+
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize database
+	if err := database.Initialize(cfg); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer database.Close()
+
+	services.SetTUFKeySecret(cfg.App.TUFKeySecret)
+
+	importService := services.NewImportService()
+
+	manifest, err := importService.ParseManifest(data, *format, *channel)
+	if err != nil {
+		log.Fatalf("Invalid manifest: %v", err)
+	}
+
+	report, err := importService.Import(*appID, manifest, models.ImportOptions{
+		DryRun:       *dryRun,
+		SkipExisting: *skipExisting,
+	})
+	if err != nil {
+		log.Fatalf("Failed to import versions: %v", err)
+	}
+
+	output, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(output))
+
+	if !report.Valid() {
+		log.Printf("Manifest validation failed with %d error(s), nothing was imported", len(report.Errors))
+		database.Close()
+		os.Exit(1)
+	}
+}
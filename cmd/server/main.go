@@ -3,21 +3,22 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
 	"github.com/Run-Panel/VerTree/internal/config"
 	"github.com/Run-Panel/VerTree/internal/database"
-	"github.com/Run-Panel/VerTree/internal/handlers/admin"
-	"github.com/Run-Panel/VerTree/internal/handlers/auth"
-	"github.com/Run-Panel/VerTree/internal/handlers/client"
+	"github.com/Run-Panel/VerTree/internal/grpcserver"
+	"github.com/Run-Panel/VerTree/internal/httpserver"
+	"github.com/Run-Panel/VerTree/internal/metrics"
 	"github.com/Run-Panel/VerTree/internal/middleware"
-	"github.com/Run-Panel/VerTree/internal/utils"
+	"github.com/Run-Panel/VerTree/internal/services"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -35,13 +36,47 @@ func main() {
 	}
 	defer database.Close()
 
+	// Expose the connection pool stats of the database as metrics
+	if sqlDB, err := database.DB.DB(); err == nil {
+		if err := metrics.RegisterDBStats(sqlDB, cfg.Database.Driver); err != nil {
+			log.Printf("Failed to register database metrics: %v", err)
+		}
+	}
+
+	// Configure the secret protecting the TUF signing keys
+	services.SetTUFKeySecret(cfg.App.TUFKeySecret)
+	if cfg.App.TUFKeySecret == "" {
+		log.Printf("Warning: TUF_KEY_SECRET is not set, TUF metadata will not be signed")
+	}
+
+	// Limit the files extracted from imported bundles
+	services.SetBundleMaxFileSize(int64(cfg.App.BundleMaxFileSize) << 20)
+
+	// Configure the polling interval hinted to clients
+	services.SetPollingConfig(time.Duration(cfg.App.CheckInterval)*time.Second, cfg.App.CheckCapacity)
+
 	// Seed default data
 	if err := database.SeedDefaultData(); err != nil {
 		log.Fatalf("Failed to seed default data: %v", err)
 	}
 
+	// Normalize the install errors recorded before error signatures were
+	go func() {
+		if updated, err := services.NewStatsService().BackfillErrorSignatures(); err != nil {
+			log.Printf("Failed to backfill error signatures: %v", err)
+		} else if updated > 0 {
+			log.Printf("Backfilled the error signatures of %d update stats", updated)
+		}
+	}()
+
+	// Keep the daily stat rollups read by the statistics endpoints up to date
+	stopStatsRollup := services.StartStatsRollup(time.Duration(cfg.App.StatsRollupInterval) * time.Second)
+
+	// Create rate limiters (shared by the REST and gRPC APIs)
+	rateLimiters := middleware.CreateRateLimiters()
+
 	// Create router
-	router := setupRouter(cfg)
+	router := httpserver.NewRouter(cfg, rateLimiters)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -49,6 +84,9 @@ func main() {
 		Handler: router,
 	}
 
+	// End release event streams so that shutdown doesn't wait for them
+	server.RegisterOnShutdown(services.CloseReleaseEventStreams)
+
 	// Start server in goroutine
 	go func() {
 		log.Printf("Server starting on %s:%s", cfg.Server.Host, cfg.Server.Port)
@@ -57,187 +95,39 @@ func main() {
 		}
 	}()
 
+	// Start the gRPC client API
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCEnabled {
+		listener, err := net.Listen("tcp", cfg.Server.Host+":"+cfg.Server.GRPCPort)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+		grpcServer = grpcserver.New(rateLimiters["client"])
+		go func() {
+			log.Printf("gRPC server starting on %s:%s", cfg.Server.Host, cfg.Server.GRPCPort)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
 
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
+	stopStatsRollup()
 
 	log.Println("Server exited")
 }
-
-func setupRouter(cfg *config.Config) *gin.Engine {
-	router := gin.New()
-
-	// Create rate limiters
-	rateLimiters := middleware.CreateRateLimiters()
-
-	// Create JWT manager
-	jwtManager := utils.NewJWTManager(cfg.App.JWTSecret)
-
-	// Global middleware (applied to all routes)
-	router.Use(middleware.Logger())
-	router.Use(middleware.RequestID())
-	router.Use(middleware.SecurityHeaders())
-	router.Use(middleware.XSSProtection())
-	router.Use(middleware.SQLInjectionProtection())
-	router.Use(middleware.CORS())
-	router.Use(gin.Recovery())
-
-	// Public health check endpoint (no auth required)
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":  "ok",
-			"service": "VerTree-Service",
-			"version": "1.0.0",
-			"region":  cfg.App.Region,
-		})
-	})
-
-	// Initialize handlers
-	authHandler := auth.NewAuthHandler(cfg.App.JWTSecret)
-	applicationHandler := admin.NewApplicationHandler()
-	versionHandler := admin.NewVersionHandler()
-	channelHandler := admin.NewChannelHandler()
-	statsHandler := admin.NewStatsHandler()
-	apiDocsHandler := admin.NewAPIDocsHandler()
-	updateHandler := client.NewUpdateHandler()
-
-	// Auth API routes (public endpoints with rate limiting)
-	authGroup := router.Group("/auth/api/v1")
-	authGroup.Use(middleware.RateLimitByType(rateLimiters, "auth"))
-	{
-		authGroup.POST("/login", authHandler.Login)
-		authGroup.POST("/refresh", authHandler.RefreshToken)
-		authGroup.POST("/logout", authHandler.Logout)
-	}
-
-	// Admin API routes (protected with authentication and admin permissions)
-	adminV1 := router.Group("/admin/api/v1")
-	adminV1.Use(middleware.RateLimitByType(rateLimiters, "admin"))
-	adminV1.Use(middleware.AuthMiddleware(jwtManager))
-	adminV1.Use(middleware.RequireAdmin()) // Requires admin or superadmin role
-	{
-		// User profile management (authenticated users)
-		adminV1.GET("/profile", authHandler.GetProfile)
-		adminV1.POST("/change-password", authHandler.ChangePassword)
-
-		// Application management
-		applications := adminV1.Group("/applications")
-		{
-			applications.GET("", applicationHandler.GetApplications)
-			applications.GET("/:id", applicationHandler.GetApplication)
-			applications.POST("", applicationHandler.CreateApplication)
-			applications.PUT("/:id", applicationHandler.UpdateApplication)
-			applications.DELETE("/:id", applicationHandler.DeleteApplication)
-
-			// Application API keys management
-			applications.GET("/:id/keys", applicationHandler.GetApplicationKeys)
-			applications.POST("/:id/keys", applicationHandler.CreateApplicationKey)
-			applications.PUT("/:id/keys/:keyId", applicationHandler.UpdateApplicationKey)
-			applications.DELETE("/:id/keys/:keyId", applicationHandler.DeleteApplicationKey)
-
-			// Application-specific channel management
-			applications.GET("/:id/channels", channelHandler.GetChannelsByApp)
-			applications.GET("/:id/channels/all", channelHandler.GetAllChannelsForApp)
-			applications.PUT("/:id/channels/:channel", channelHandler.EnableChannelForApp)
-			applications.DELETE("/:id/channels/:channel", channelHandler.DisableChannelForApp)
-
-			// Application-specific version management - 使用相同的参数名 :id
-			appVersions := applications.Group("/:id/versions")
-			{
-				appVersions.POST("/upload", versionHandler.CreateVersionWithUpload)
-				appVersions.PUT("/:version_id/upload", versionHandler.UpdateVersionWithUpload)
-			}
-		}
-
-		// Global version management (cross-application)
-		versions := adminV1.Group("/versions")
-		{
-			versions.POST("", versionHandler.CreateVersion)
-			versions.POST("/upload", versionHandler.CreateVersionWithUploadGlobal)
-			versions.GET("", versionHandler.GetVersions)
-			versions.GET("/:id", versionHandler.GetVersion)
-			versions.PUT("/:id", versionHandler.UpdateVersion)
-			versions.PUT("/:id/upload", versionHandler.UpdateVersionWithUploadGlobal)
-			versions.DELETE("/:id", versionHandler.DeleteVersion)
-			versions.POST("/:id/publish", versionHandler.PublishVersion)
-			versions.POST("/:id/unpublish", versionHandler.UnpublishVersion)
-		}
-
-		// Channel management
-		channels := adminV1.Group("/channels")
-		{
-			channels.GET("", channelHandler.GetChannels)
-			channels.GET("/:id", channelHandler.GetChannel)
-			channels.POST("", channelHandler.CreateChannel)
-			channels.PUT("/:id", channelHandler.UpdateChannel)
-			channels.DELETE("/:id", channelHandler.DeleteChannel)
-		}
-
-		// Statistics
-		adminV1.GET("/stats", statsHandler.GetStats)
-		adminV1.GET("/stats/distribution", statsHandler.GetVersionDistribution)
-		adminV1.GET("/stats/regions", statsHandler.GetRegionDistribution)
-
-		// API Documentation
-		adminV1.GET("/docs", apiDocsHandler.GetAPIDocs)
-
-		// Admin management (superadmin only)
-		admins := adminV1.Group("/admins")
-		admins.Use(middleware.RequireSuperAdmin()) // Requires superadmin role
-		{
-			admins.GET("", authHandler.ListAdmins)
-			admins.GET("/:id", authHandler.GetAdmin)
-			admins.POST("", authHandler.CreateAdmin)
-			admins.PUT("/:id", authHandler.UpdateAdmin)
-			admins.DELETE("/:id", authHandler.DeleteAdmin)
-		}
-	}
-
-	// Client API routes (public with rate limiting and API key authentication)
-	clientV1 := router.Group("/api/v1")
-	clientV1.Use(middleware.RateLimitByType(rateLimiters, "client"))
-	clientV1.Use(middleware.APIKeyAuth()) // Require API key authentication
-	{
-		clientV1.POST("/check-update", middleware.RequirePermission("check_update"), updateHandler.CheckUpdate)
-		clientV1.POST("/download-started", middleware.RequirePermission("download"), updateHandler.DownloadStarted)
-		clientV1.POST("/install-result", middleware.RequirePermission("install"), updateHandler.InstallResult)
-		clientV1.GET("/versions", middleware.RequirePermission("check_update"), updateHandler.GetVersions)
-	}
-
-	// Serve static files for admin frontend (public access)
-	router.Static("/admin-ui", "./web/admin")
-
-	// Serve uploaded files (public access for download)
-	router.Static("/uploads", "./uploads")
-
-	// SPA fallback for admin frontend - handle all sub-routes
-	router.NoRoute(func(c *gin.Context) {
-		// Check if the request is for admin-ui
-		if strings.HasPrefix(c.Request.URL.Path, "/admin-ui/") {
-			// Serve index.html for SPA routing
-			c.File("./web/admin/index.html")
-			return
-		}
-		// Default 404 for other routes
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Route not found",
-			"path":  c.Request.URL.Path,
-		})
-	})
-
-	// Redirect root to admin frontend
-	router.GET("/", func(c *gin.Context) {
-		c.Redirect(http.StatusMovedPermanently, "/admin-ui/")
-	})
-
-	return router
-}
@@ -101,6 +101,7 @@ case $choice in
             echo "- POSTGRES_PASSWORD: PostgreSQL密码"  
             echo "- REDIS_PASSWORD: Redis密码"
             echo "- JWT_SECRET: JWT签名密钥"
+            echo "- TUF_KEY_SECRET: TUF签名密钥的加密密钥"
             echo "- DOMAIN: 生产域名"
             echo ""
             echo "生成JWT密钥: openssl rand -hex 32"
@@ -19,6 +19,14 @@ type Config struct {
 type ServerConfig struct {
 	Port string
 	Host string
+
+	// gRPC client API, served alongside the REST API when enabled
+	GRPCEnabled bool
+	GRPCPort    string
+
+	// MetricsToken protects the Prometheus metrics endpoint, which is open when it is empty
+	// and not served at all in production
+	MetricsToken string
 }
 
 // DatabaseConfig holds database configuration
@@ -39,6 +47,23 @@ type AppConfig struct {
 	Domain      string
 	UploadPath  string
 	JWTSecret   string
+
+	// TUFKeySecret encrypts the TUF role keys stored in the database. It has no default:
+	// without it no keys are generated and no metadata is signed.
+	TUFKeySecret string
+
+	// CheckInterval is the default number of seconds between update checks hinted to
+	// clients, CheckCapacity the number of update checks per minute the server handles
+	// before it asks clients to check less often
+	CheckInterval int
+	CheckCapacity int
+
+	// StatsRollupInterval is the number of seconds between refreshes of the daily stat
+	// rollups the statistics endpoints read
+	StatsRollupInterval int
+
+	// BundleMaxFileSize is the largest file in MB extracted from an imported bundle
+	BundleMaxFileSize int
 }
 
 // Load loads configuration from environment variables
@@ -52,6 +77,11 @@ func Load() *Config {
 		Server: ServerConfig{
 			Port: getEnv("SERVER_PORT", "8080"),
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
+
+			GRPCEnabled: getEnvAsBool("GRPC_ENABLED", false),
+			GRPCPort:    getEnv("GRPC_PORT", "9090"),
+
+			MetricsToken: getEnv("METRICS_TOKEN", ""),
 		},
 		Database: DatabaseConfig{
 			Driver:   getEnv("DB_DRIVER", "sqlite"),
@@ -68,6 +98,15 @@ func Load() *Config {
 			Domain:      getEnv("DOMAIN", "localhost"),
 			UploadPath:  getEnv("UPLOAD_PATH", "./uploads"),
 			JWTSecret:   getEnv("JWT_SECRET", "fed9219b65a21a3357d5927873d966d63cac9a2e86e44c6001b447a01c3bb96b"),
+
+			TUFKeySecret: getEnv("TUF_KEY_SECRET", ""),
+
+			CheckInterval: getEnvAsInt("CHECK_INTERVAL", 3600),
+			CheckCapacity: getEnvAsInt("CHECK_CAPACITY", 6000),
+
+			StatsRollupInterval: getEnvAsInt("STATS_ROLLUP_INTERVAL", 300),
+
+			BundleMaxFileSize: getEnvAsInt("BUNDLE_MAX_FILE_SIZE", 2048),
 		},
 	}
 
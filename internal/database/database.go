@@ -94,22 +94,40 @@ func Initialize(cfg *config.Config) error {
 		return fmt.Errorf("failed to run SQL migrations: %w", err)
 	}
 
+	// Some SQL migrations rebuild tables with a fixed column list, so restore
+	// columns that were added to the models after those migrations were written
+	if err := RestoreMissingColumns(); err != nil {
+		return fmt.Errorf("failed to restore missing columns: %w", err)
+	}
+
 	log.Printf("Successfully connected to %s database", cfg.Database.Driver)
 	return nil
 }
 
-// AutoMigrate runs database migrations
-func AutoMigrate() error {
-	err := DB.AutoMigrate(
+// migratedModels lists the models whose tables are managed by AutoMigrate
+func migratedModels() []interface{} {
+	return []interface{}{
 		&models.Application{},
 		&models.ApplicationKey{},
 		&models.Version{},
+		&models.VersionLocalization{},
+		&models.VersionArtifact{},
+		&models.TUFKey{},
+		&models.TUFMetadata{},
+		&models.ReleaseLine{},
 		&models.Channel{},
 		&models.UpdateRule{},
 		&models.UpdateStat{},
+		&models.StatRollup{},
+		&models.Client{},
 		&models.Admin{},
 		&models.RefreshToken{},
-	)
+	}
+}
+
+// AutoMigrate runs database migrations
+func AutoMigrate() error {
+	err := DB.AutoMigrate(migratedModels()...)
 	if err != nil {
 		return fmt.Errorf("failed to run auto migration: %w", err)
 	}
@@ -118,6 +136,30 @@ func AutoMigrate() error {
 	return nil
 }
 
+// RestoreMissingColumns adds model columns that are missing from existing tables
+// without altering or rebuilding the tables
+func RestoreMissingColumns() error {
+	migrator := DB.Migrator()
+	for _, model := range migratedModels() {
+		stmt := &gorm.Statement{DB: DB}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || migrator.HasColumn(model, field.DBName) {
+				continue
+			}
+			if err := migrator.AddColumn(model, field.Name); err != nil {
+				return fmt.Errorf("failed to add column %s.%s: %w", stmt.Schema.Table, field.DBName, err)
+			}
+			log.Printf("Restored missing column %s.%s", stmt.Schema.Table, field.DBName)
+		}
+	}
+
+	return nil
+}
+
 // SeedDefaultData seeds the database with default data
 func SeedDefaultData() error {
 	// Check if channels already exist
@@ -0,0 +1,124 @@
+package grpcserver
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/Run-Panel/VerTree/internal/middleware"
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+	vertreev1 "github.com/Run-Panel/VerTree/pkg/api/vertree/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// methodPermissions maps each RPC to the API key permission required by its REST counterpart
+var methodPermissions = map[string]string{
+	vertreev1.UpdateService_CheckUpdate_FullMethodName:     "check_update",
+	vertreev1.UpdateService_GetVersions_FullMethodName:     "check_update",
+	vertreev1.UpdateService_DownloadStarted_FullMethodName: "download",
+	vertreev1.UpdateService_InstallResult_FullMethodName:   "install",
+}
+
+// authKey is the context key of the authenticated application
+type authKey struct{}
+
+// authInfo holds the application and API key a call was authenticated with
+type authInfo struct {
+	app *models.Application
+	key *models.ApplicationKey
+}
+
+// authFromContext returns the authentication info set by the auth interceptor
+func authFromContext(ctx context.Context) *authInfo {
+	info, _ := ctx.Value(authKey{}).(*authInfo)
+	return info
+}
+
+// rateLimitInterceptor applies the client rate limit per peer IP, like the REST client API.
+// Rejected calls get a "retry-after" header with the seconds until the limit resets.
+func rateLimitInterceptor(limiter *middleware.RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if limiter != nil && !limiter.Allow(peerIP(ctx)) {
+			seconds := int64(math.Ceil(limiter.RetryAfter(peerIP(ctx)).Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.FormatInt(seconds, 10)))
+			return nil, status.Error(codes.ResourceExhausted, "Rate limit exceeded")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authInterceptor authenticates calls with the "authorization" metadata "Bearer <app_id>:<api_key>"
+// and checks the permission required by the method, mirroring APIKeyAuth and RequirePermission
+func authInterceptor(appService *services.ApplicationService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		authHeader := firstMetadata(ctx, "authorization")
+		if authHeader == "" {
+			return nil, status.Error(codes.Unauthenticated, "Missing authorization metadata")
+		}
+
+		const bearerPrefix = "Bearer "
+		if !strings.HasPrefix(authHeader, bearerPrefix) {
+			return nil, status.Error(codes.Unauthenticated, "Invalid authorization format. Use 'Bearer <app_id>:<api_key>'")
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(authHeader, bearerPrefix), ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, status.Error(codes.Unauthenticated, "Invalid token format. Use '<app_id>:<api_key>'")
+		}
+
+		app, key, err := appService.ValidateAPIKey(parts[0], parts[1])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "Invalid credentials")
+		}
+
+		permission, ok := methodPermissions[info.FullMethod]
+		if !ok || !key.Permissions.Has(permission) {
+			return nil, status.Error(codes.PermissionDenied, "Insufficient permissions")
+		}
+
+		return handler(context.WithValue(ctx, authKey{}, &authInfo{app: app, key: key}), req)
+	}
+}
+
+// firstMetadata returns the first value of an incoming metadata key
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// peerIP returns the IP address of the calling peer
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// clientIP returns the client IP recorded in statistics, preferring x-forwarded-for like the REST API
+func clientIP(ctx context.Context) string {
+	if forwardedFor := firstMetadata(ctx, "x-forwarded-for"); forwardedFor != "" {
+		return forwardedFor
+	}
+	return peerIP(ctx)
+}
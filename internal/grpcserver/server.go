@@ -0,0 +1,211 @@
+// Package grpcserver serves the client update API over gRPC, alongside the REST API and
+// backed by the same services.
+package grpcserver
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/middleware"
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+	vertreev1 "github.com/Run-Panel/VerTree/pkg/api/vertree/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements vertreev1.UpdateServiceServer
+type Server struct {
+	vertreev1.UnimplementedUpdateServiceServer
+	updateService  *services.UpdateService
+	versionService *services.VersionService
+}
+
+// New creates a gRPC server with the update service registered. Calls are rate limited
+// with limiter (may be nil) and authenticated with application API keys.
+func New(limiter *middleware.RateLimiter) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		rateLimitInterceptor(limiter),
+		authInterceptor(services.NewApplicationService()),
+	))
+	vertreev1.RegisterUpdateServiceServer(server, &Server{
+		updateService:  services.NewUpdateService(),
+		versionService: services.NewVersionService(),
+	})
+	return server
+}
+
+// CheckUpdate checks whether a newer version is available for the client
+func (s *Server) CheckUpdate(ctx context.Context, req *vertreev1.CheckUpdateRequest) (*vertreev1.CheckUpdateResponse, error) {
+	auth := authFromContext(ctx)
+
+	appID := req.GetAppId()
+	if appID == "" {
+		appID = auth.app.AppID
+	} else if appID != auth.app.AppID {
+		return nil, status.Error(codes.PermissionDenied, "app_id does not match the API key")
+	}
+
+	locale := req.GetLocale()
+	if locale == "" {
+		locale = firstMetadata(ctx, "accept-language")
+	}
+
+	response, err := s.updateService.CheckUpdate(&models.CheckUpdateRequest{
+		AppID:            appID,
+		CurrentVersion:   req.GetCurrentVersion(),
+		Channel:          req.GetChannel(),
+		ClientID:         req.GetClientId(),
+		Region:           req.GetRegion(),
+		Arch:             req.GetArch(),
+		OS:               req.GetOs(),
+		Locale:           locale,
+		IncludeChangelog: req.GetIncludeChangelog(),
+		ReleaseLine:      req.GetReleaseLine(),
+		OSVersion:        req.GetOsVersion(),
+		Dependencies:     req.GetDependencies(),
+		Capabilities:     req.GetCapabilities(),
+	}, clientIP(ctx))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Failed to check for updates: %v", err)
+	}
+
+	return toCheckUpdateResponse(response), nil
+}
+
+// DownloadStarted records that the client started downloading a version
+func (s *Server) DownloadStarted(ctx context.Context, req *vertreev1.DownloadStartedRequest) (*vertreev1.DownloadStartedResponse, error) {
+	auth := authFromContext(ctx)
+
+	if req.GetVersion() == "" || req.GetClientId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "version and client_id are required")
+	}
+
+	// Don't fail the call if recording fails
+	if err := s.updateService.RecordDownloadStart(auth.app.AppID, req.GetVersion(), req.GetClientId(), clientIP(ctx)); err != nil {
+		log.Printf("Failed to record download start: %v", err)
+	}
+
+	return &vertreev1.DownloadStartedResponse{}, nil
+}
+
+// InstallResult records the result of installing a version
+func (s *Server) InstallResult(ctx context.Context, req *vertreev1.InstallResultRequest) (*vertreev1.InstallResultResponse, error) {
+	auth := authFromContext(ctx)
+
+	if req.GetVersion() == "" || req.GetClientId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "version and client_id are required")
+	}
+
+	// Don't fail the call if recording fails
+	if err := s.updateService.RecordInstallResult(auth.app.AppID, req.GetVersion(), req.GetClientId(), req.GetSuccess(), req.GetErrorMessage(), clientIP(ctx)); err != nil {
+		log.Printf("Failed to record install result: %v", err)
+	}
+
+	return &vertreev1.InstallResultResponse{}, nil
+}
+
+// GetVersions lists the versions of the authenticated application
+func (s *Server) GetVersions(ctx context.Context, req *vertreev1.GetVersionsRequest) (*vertreev1.GetVersionsResponse, error) {
+	auth := authFromContext(ctx)
+
+	limit := int(req.GetLimit())
+	if limit < 1 {
+		limit = 10
+	}
+
+	publishedOnly := true
+	if req.PublishedOnly != nil {
+		publishedOnly = req.GetPublishedOnly()
+	}
+
+	locale := req.GetLocale()
+	if locale == "" {
+		locale = firstMetadata(ctx, "accept-language")
+	}
+
+	versions, err := s.versionService.GetVersionsForApp(auth.app.AppID, req.GetChannel(), limit, publishedOnly)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get versions: %v", err)
+	}
+
+	applied, err := s.versionService.LocalizeVersions(versions, locale)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to localize versions: %v", err)
+	}
+
+	response := &vertreev1.GetVersionsResponse{}
+	for _, version := range versions {
+		info := &vertreev1.VersionInfo{
+			Version:           version.Version,
+			Channel:           version.Channel,
+			Title:             version.Title,
+			Description:       version.Description,
+			ReleaseNotes:      version.ReleaseNotes,
+			DownloadUrl:       version.FileURL,
+			FileSize:          version.FileSize,
+			FileChecksum:      version.FileChecksum,
+			IsForced:          version.IsForced,
+			MinUpgradeVersion: version.MinUpgradeVersion,
+			Locale:            applied[version.ID],
+		}
+		if version.IsPublished {
+			info.PublishedAt = timestamp(version.PublishTime)
+		}
+		response.Versions = append(response.Versions, info)
+	}
+
+	return response, nil
+}
+
+// toCheckUpdateResponse converts a check update response to its protobuf message
+func toCheckUpdateResponse(response *models.CheckUpdateResponse) *vertreev1.CheckUpdateResponse {
+	result := &vertreev1.CheckUpdateResponse{
+		HasUpdate:         response.HasUpdate,
+		LatestVersion:     response.LatestVersion,
+		DownloadUrl:       response.DownloadURL,
+		FileSize:          response.FileSize,
+		FileChecksum:      response.FileChecksum,
+		IsForced:          response.IsForced,
+		Title:             response.Title,
+		Description:       response.Description,
+		ReleaseNotes:      response.ReleaseNotes,
+		MinUpgradeVersion: response.MinUpgradeVersion,
+		Locale:            response.Locale,
+		ReleaseLine:       response.ReleaseLine,
+		NextCheckAfter:    int32(response.NextCheckAfter),
+	}
+
+	for _, entry := range response.Changelog {
+		result.Changelog = append(result.Changelog, &vertreev1.ChangelogEntry{
+			Version:         entry.Version,
+			Title:           entry.Title,
+			ReleaseNotes:    entry.ReleaseNotes,
+			BreakingChanges: entry.BreakingChanges,
+			IsForced:        entry.IsForced,
+			PublishTime:     timestamp(entry.PublishTime),
+			Locale:          entry.Locale,
+		})
+	}
+
+	if response.SupportStatus != nil {
+		result.SupportStatus = &vertreev1.SupportStatus{
+			Status:       response.SupportStatus.Status,
+			DeprecatedAt: timestamp(response.SupportStatus.DeprecatedAt),
+			EolAt:        timestamp(response.SupportStatus.EOLAt),
+		}
+	}
+
+	return result
+}
+
+// timestamp converts an optional time to a protobuf timestamp
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
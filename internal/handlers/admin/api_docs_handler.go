@@ -25,7 +25,7 @@ func (h *APIDocsHandler) GetAPIDocs(c *gin.Context) {
 		"authentication": map[string]interface{}{
 			"type":        "Bearer Token",
 			"format":      "Bearer <app_id>:<api_key>",
-			"description": "所有客户端API都需要使用应用ID和API密钥进行认证",
+			"description": "所有客户端API都需要使用应用ID和API密钥进行认证。公开应用 (is_public) 的 check-update 和 versions 也可以不带密钥匿名访问，此时通过 app_id 请求体字段或查询参数指定应用，按IP限制为每分钟 60 次，且只能看到已发布的版本",
 			"example":     "Bearer app_abc123:sk_def456789",
 		},
 		"endpoints": []map[string]interface{}{
@@ -34,20 +34,26 @@ func (h *APIDocsHandler) GetAPIDocs(c *gin.Context) {
 				"method":      "POST",
 				"path":        "/check-update",
 				"permission":  "check_update",
-				"description": "检查应用是否有可用的更新版本",
+				"description": "检查应用是否有可用的更新版本。next_check_after 为客户端下次检查前应等待的秒数，由应用通道的 check_interval（未设置时为服务器默认值）和服务器当前负载决定，服务器繁忙时会延长",
 				"request": map[string]interface{}{
 					"headers": map[string]string{
 						"Authorization": "Bearer <app_id>:<api_key>",
 						"Content-Type":  "application/json",
 					},
 					"body": map[string]interface{}{
-						"app_id":          "string (required) - 应用ID，与Authorization中的app_id一致",
-						"current_version": "string (required) - 当前版本号，如 'v1.2.0'",
-						"channel":         "string (required) - 更新通道: stable, beta, alpha",
-						"client_id":       "string (required) - 客户端唯一标识",
-						"region":          "string (optional) - 客户端地区，如 'cn', 'us'",
-						"arch":            "string (optional) - 系统架构，如 'amd64', 'arm64'",
-						"os":              "string (optional) - 操作系统，如 'linux', 'windows', 'darwin'",
+						"app_id":            "string (required) - 应用ID，与Authorization中的app_id一致",
+						"current_version":   "string (required) - 当前版本号，如 'v1.2.0'",
+						"channel":           "string (required) - 更新通道: stable, beta, alpha",
+						"client_id":         "string (required) - 客户端唯一标识",
+						"region":            "string (optional) - 客户端地区，如 'cn', 'us'",
+						"arch":              "string (optional) - 系统架构，如 'amd64', 'arm64'",
+						"os":                "string (optional) - 操作系统，如 'linux', 'windows', 'darwin'",
+						"locale":            "string (optional) - 首选语言，如 'zh-CN'，未提供时使用 Accept-Language 请求头",
+						"include_changelog": "boolean (optional) - 返回当前版本与最新版本之间所有已发布版本的更新日志",
+						"os_version":        "string (optional) - 操作系统版本，如 '10.15.7'，用于匹配版本的系统版本要求",
+						"dependencies":      "object (optional) - 已安装的配套应用及版本，如 {\"agent\": \"2.1.0\"}",
+						"capabilities":      "array (optional) - 客户端支持的能力，如 [\"delta_update\"]",
+						"release_line":      "string (optional) - 固定的发布线名称，如 '1.x-lts'，只接收该发布线内的最新版本",
 					},
 					"example": map[string]interface{}{
 						"app_id":          "app_abc123def456",
@@ -66,7 +72,8 @@ func (h *APIDocsHandler) GetAPIDocs(c *gin.Context) {
 							"code":    200,
 							"message": "success",
 							"data": map[string]interface{}{
-								"has_update": false,
+								"has_update":       false,
+								"next_check_after": 3600,
 							},
 						},
 						"example_has_update": map[string]interface{}{
@@ -83,6 +90,7 @@ func (h *APIDocsHandler) GetAPIDocs(c *gin.Context) {
 								"description":         "修复了一些重要bug",
 								"release_notes":       "## 更新内容\n\n- 修复登录问题\n- 优化性能",
 								"min_upgrade_version": "v1.0.0",
+								"next_check_after":    3600,
 							},
 						},
 					},
@@ -108,6 +116,14 @@ func (h *APIDocsHandler) GetAPIDocs(c *gin.Context) {
 							"message": "Insufficient permissions",
 						},
 					},
+					"429": map[string]interface{}{
+						"description": "超出请求频率限制，Retry-After 响应头给出需要等待的秒数",
+						"example": map[string]interface{}{
+							"code":    429,
+							"message": "Rate limit exceeded",
+							"error":   "RATE_LIMIT_EXCEEDED",
+						},
+					},
 				},
 			},
 			{
@@ -186,6 +202,193 @@ func (h *APIDocsHandler) GetAPIDocs(c *gin.Context) {
 					},
 				},
 			},
+			{
+				"name":        "批量遥测上报",
+				"method":      "POST",
+				"path":        "/telemetry/batch",
+				"permission":  "download / install",
+				"description": "批量上报客户端离线期间积累的下载和安装事件（每批最多 500 条），在一个事务中写入。事件按 event_id 去重，重试同一批次不会产生重复记录。download 事件需要 download 权限，install 事件需要 install 权限",
+				"request": map[string]interface{}{
+					"headers": map[string]string{
+						"Authorization": "Bearer <app_id>:<api_key>",
+						"Content-Type":  "application/json",
+					},
+					"body": map[string]interface{}{
+						"client_id":      "string (optional) - 客户端唯一标识，事件未指定时使用",
+						"client_version": "string (optional) - 客户端当前版本",
+						"events":         "array (required) - 事件列表，每个事件包含 event_id (required, 客户端生成的唯一ID，如 UUID)、type (required, download 或 install)、version (required)、client_id、success、error_message、occurred_at (RFC3339 客户端时间)",
+					},
+					"example": map[string]interface{}{
+						"client_id":      "client_unique_id_12345",
+						"client_version": "v1.2.2",
+						"events": []map[string]interface{}{
+							{
+								"event_id":    "5f0c6a1e-8d2b-4a57-9e0b-3c1f2d4e5a6b",
+								"type":        "download",
+								"version":     "v1.2.3",
+								"occurred_at": "2024-01-15T08:30:00Z",
+							},
+							{
+								"event_id":      "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d",
+								"type":          "install",
+								"version":       "v1.2.3",
+								"success":       false,
+								"error_message": "Checksum verification failed",
+								"occurred_at":   "2024-01-15T08:31:12Z",
+							},
+						},
+					},
+				},
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "记录成功，已记录过的事件标记为 duplicate",
+						"example": map[string]interface{}{
+							"code":    200,
+							"message": "success",
+							"data": map[string]interface{}{
+								"accepted":   1,
+								"duplicates": 1,
+								"results": []map[string]string{
+									{"event_id": "5f0c6a1e-8d2b-4a57-9e0b-3c1f2d4e5a6b", "status": "duplicate"},
+									{"event_id": "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d", "status": "accepted"},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				"name":        "检查更新 (v2)",
+				"method":      "POST",
+				"path":        "/api/v2/check-update",
+				"permission":  "check_update",
+				"description": "客户端API v2 的检查更新。请求与 v1 相同；v2 的 download-started、install-result、telemetry/batch 和 versions 与 v1 行为一致。v2 的错误响应在 error 字段返回稳定的错误码，便于客户端按错误码处理；没有更新时通过 reason 说明原因。指定了 os 且版本有平台构建、但没有该平台的构建时，v2 不提供更新 (no_build_for_platform)，而 v1 返回默认下载地址",
+				"request": map[string]interface{}{
+					"headers": map[string]string{
+						"Authorization": "Bearer <app_id>:<api_key>",
+						"Content-Type":  "application/json",
+					},
+					"example": map[string]interface{}{
+						"app_id":          "app_abc123",
+						"current_version": "v1.2.2",
+						"channel":         "stable",
+						"client_id":       "client_unique_id_12345",
+						"os_version":      "9.0.0",
+					},
+				},
+				"error_codes": map[string]string{
+					"INVALID_REQUEST":        "400 - 请求参数无效",
+					"MISSING_CREDENTIALS":    "401 - 缺少 Authorization 请求头",
+					"INVALID_CREDENTIALS":    "401 - 应用ID或API密钥无效",
+					"PERMISSION_DENIED":      "403 - API密钥没有所需权限",
+					"RATE_LIMIT_EXCEEDED":    "429 - 超出请求频率限制",
+					"APP_NOT_FOUND":          "404 - 应用不存在",
+					"APP_INACTIVE":           "403 - 应用已停用",
+					"APP_MISMATCH":           "403 - app_id 与API密钥所属应用不一致",
+					"CHANNEL_NOT_FOUND":      "404 - 通道不存在",
+					"CHANNEL_DISABLED":       "403 - 通道未对该应用启用",
+					"CHANNEL_INACTIVE":       "403 - 通道已停用",
+					"RELEASE_LINE_NOT_FOUND": "404 - 发布线不存在",
+					"RELEASE_LINE_INACTIVE":  "403 - 发布线已停用",
+					"INTERNAL_ERROR":         "500 - 服务器内部错误",
+				},
+				"no_update_reasons": map[string]string{
+					"up_to_date":            "当前已是最新版本",
+					"no_release":            "通道（或发布线）中没有可用的已发布版本",
+					"incompatible":          "有更新的版本，但客户端的系统版本、依赖或能力不满足其要求",
+					"below_minimum_version": "当前版本低于最新版本的最小升级版本",
+					"excluded_by_rollout":   "客户端尚未被灰度发布规则覆盖",
+					"no_build_for_platform": "最新版本没有客户端平台的构建",
+				},
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "没有更新时包含原因",
+						"example": map[string]interface{}{
+							"code":    200,
+							"message": "success",
+							"data": map[string]interface{}{
+								"has_update":     false,
+								"support_status": map[string]string{"status": "supported"},
+								"reason":         "incompatible",
+								"reason_detail":  "v1.3.0: os version 9.0.0 is below minimum 10.0.0",
+							},
+						},
+					},
+					"404": map[string]interface{}{
+						"description": "通道不存在",
+						"example": map[string]interface{}{
+							"code":    404,
+							"message": "channel beta is not enabled for this application",
+							"error":   "CHANNEL_NOT_FOUND",
+						},
+					},
+				},
+			},
+			{
+				"name":        "发布事件订阅",
+				"method":      "GET",
+				"path":        "/events",
+				"permission":  "check_update",
+				"description": "通过 Server-Sent Events 订阅应用的发布通知：新版本发布 (version_published) 或强制更新 (forced_update)。收到事件后客户端应调用 check-update。断线重连时通过 Last-Event-ID 补发错过的事件；若事件已无法补发，服务端发送 resync 事件",
+				"request": map[string]interface{}{
+					"headers": map[string]string{
+						"Authorization": "Bearer <app_id>:<api_key>",
+						"Accept":        "text/event-stream",
+						"Last-Event-ID": "string (optional) - 最后收到的事件ID，也可用 last_event_id 查询参数",
+					},
+					"query": map[string]string{
+						"channel": "string (optional) - 只接收该通道的事件",
+					},
+				},
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "text/event-stream 事件流，每 25 秒发送一次心跳注释",
+						"example":     "id: 1792350008866\nevent: forced_update\ndata: {\"id\":1792350008866,\"type\":\"forced_update\",\"app_id\":\"app_abc123\",\"channel\":\"stable\",\"version\":\"v1.2.3\",\"is_forced\":true,\"message\":\"security fix\"}\n\n",
+					},
+				},
+			},
+			{
+				"name":        "TUF 元数据",
+				"method":      "GET",
+				"path":        "/tuf/:file",
+				"permission":  "check_update",
+				"description": "获取应用的 TUF (The Update Framework) 签名元数据，用于检测回滚和冻结攻击。file 为 root.json、targets.json、snapshot.json、timestamp.json，或历史根元数据 N.root.json（用于跟随根密钥轮换）",
+				"request": map[string]interface{}{
+					"headers": map[string]string{
+						"Authorization": "Bearer <app_id>:<api_key>",
+					},
+				},
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "规范化 JSON 格式的签名元数据，targets 中的 custom 字段包含版本号、通道和下载地址",
+						"example": map[string]interface{}{
+							"signed": map[string]interface{}{
+								"_type":        "timestamp",
+								"spec_version": "1.0.31",
+								"version":      12,
+								"expires":      "2025-01-02T00:00:00Z",
+								"meta": map[string]interface{}{
+									"snapshot.json": map[string]interface{}{
+										"version": 12,
+										"length":  367,
+										"hashes":  map[string]string{"sha256": "194d58df..."},
+									},
+								},
+							},
+							"signatures": []map[string]string{
+								{"keyid": "c00e40a6...", "sig": "ed6d47ea..."},
+							},
+						},
+					},
+					"404": map[string]interface{}{
+						"description": "元数据不存在",
+						"example": map[string]interface{}{
+							"code":    404,
+							"message": "Metadata not found",
+						},
+					},
+				},
+			},
 		},
 		"examples": map[string]interface{}{
 			"curl": map[string]interface{}{
@@ -356,11 +559,11 @@ func checkUpdate(appID, apiKey, currentVersion string) (*CheckUpdateResponse, er
 		"best_practices": []map[string]interface{}{
 			{
 				"title":   "API密钥安全",
-				"content": "API密钥包含敏感信息，请妥善保管：\n1. 不要在客户端代码中硬编码API密钥\n2. 使用环境变量或配置文件存储密钥\n3. 定期轮换API密钥\n4. 为不同环境（开发、测试、生产）使用不同的密钥",
+				"content": "API密钥包含敏感信息，请妥善保管：\n1. 不要在客户端代码中硬编码API密钥\n2. 使用环境变量或配置文件存储密钥\n3. 定期轮换API密钥\n4. 为不同环境（开发、测试、生产）使用不同的密钥\n5. 开源应用无法在发布的程序中保密密钥，请将应用设为公开 (is_public) 并匿名检查更新",
 			},
 			{
 				"title":   "错误处理",
-				"content": "正确处理API错误响应：\n1. 检查HTTP状态码\n2. 解析响应中的错误信息\n3. 实现重试机制（针对临时性错误）\n4. 记录错误日志便于调试",
+				"content": "正确处理API错误响应：\n1. 检查HTTP状态码\n2. 解析响应中的错误信息\n3. 实现重试机制（针对临时性错误），收到 429 时按 Retry-After 响应头等待后重试\n4. 记录错误日志便于调试",
 			},
 			{
 				"title":   "版本比较",
@@ -368,7 +571,7 @@ func checkUpdate(appID, apiKey, currentVersion string) (*CheckUpdateResponse, er
 			},
 			{
 				"title":   "更新流程",
-				"content": "推荐的更新流程：\n1. 检查更新 (check-update)\n2. 下载文件并验证校验和\n3. 记录下载开始 (download-started)\n4. 安装更新\n5. 记录安装结果 (install-result)\n6. 重启应用（如需要）",
+				"content": "推荐的更新流程：\n1. 检查更新 (check-update)，并按响应中的 next_check_after 安排下次检查\n2. 下载文件并验证校验和\n3. 记录下载开始 (download-started)\n4. 安装更新\n5. 记录安装结果 (install-result)\n6. 重启应用（如需要）",
 			},
 		},
 	}
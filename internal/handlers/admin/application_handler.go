@@ -305,3 +305,27 @@ func (h *ApplicationHandler) DeleteApplicationKey(c *gin.Context) {
 		"message": "Application key deleted successfully",
 	}))
 }
+
+// RotateFeedToken handles POST /admin/api/v1/applications/:id/feed-token
+// The new token is only returned once; the previous token stops working immediately.
+func (h *ApplicationHandler) RotateFeedToken(c *gin.Context) {
+	localizer := getLocalizer(c)
+
+	appID := c.Param("id")
+	if appID == "" {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse(localizer.Get(i18n.ErrApplicationIDRequired), nil))
+		return
+	}
+
+	token, err := h.appService.RotateFeedToken(appID)
+	if err != nil {
+		if err.Error() == "application not found" {
+			c.JSON(http.StatusNotFound, models.NotFoundResponse(localizer.Get(i18n.ErrApplicationNotFound)))
+		} else {
+			c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to rotate feed token", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(token))
+}
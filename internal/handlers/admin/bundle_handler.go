@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"archive/zip"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// BundleHandler handles admin application export/import endpoints
+type BundleHandler struct {
+	bundleService *services.BundleService
+}
+
+// NewBundleHandler creates a new bundle handler
+func NewBundleHandler() *BundleHandler {
+	return &BundleHandler{
+		bundleService: services.NewBundleService(),
+	}
+}
+
+// ExportApplication handles GET /admin/api/v1/applications/:id/export
+// Set include_files=true to add the files uploaded to this instance to the archive.
+func (h *BundleHandler) ExportApplication(c *gin.Context) {
+	bundle, err := h.bundleService.ExportApplication(c.Param("id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, models.NotFoundResponse("Application not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to export application", err))
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.zip", bundle.Application.Name, bundle.ExportedAt.Format("20060102150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	// The archive is streamed, so errors can't be reported to the client anymore
+	if err := h.bundleService.WriteBundle(c.Writer, bundle, c.Query("include_files") == "true"); err != nil {
+		log.Printf("Failed to write bundle for application %s: %v", bundle.Application.AppID, err)
+	}
+}
+
+// ImportApplication handles POST /admin/api/v1/applications/import
+// The bundle archive is sent as the "bundle" file of a multipart form. The on_conflict
+// query parameter (fail, merge, overwrite) decides what happens if the application exists.
+// Overwriting replaces the bundled settings and versions but keeps versions the bundle lacks.
+func (h *BundleHandler) ImportApplication(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.UnauthorizedResponse("User not authenticated"))
+		return
+	}
+
+	file, header, err := c.Request.FormFile("bundle")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Bundle file is required", err))
+		return
+	}
+	defer file.Close()
+
+	archive, err := zip.NewReader(file, header.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid bundle archive", err))
+		return
+	}
+
+	report, err := h.bundleService.ImportBundle(archive, c.DefaultQuery("on_conflict", models.BundleConflictFail), adminID.(uint))
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, models.ErrorResponseWithCode(http.StatusConflict, "Application already exists", err))
+			return
+		}
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Failed to import application", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponseWithMessage("Application imported successfully", report))
+}
@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ClientHandler handles admin endpoints for the client inventory of applications
+type ClientHandler struct {
+	clientService *services.ClientService
+}
+
+// NewClientHandler creates a new client handler
+func NewClientHandler() *ClientHandler {
+	return &ClientHandler{
+		clientService: services.NewClientService(),
+	}
+}
+
+// GetClients handles GET /admin/api/v1/applications/:id/clients
+func (h *ClientHandler) GetClients(c *gin.Context) {
+	var req models.ClientListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid query parameters", err))
+		return
+	}
+
+	response, err := h.clientService.ListClients(c.Param("id"), &req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, models.NotFoundResponse("Application not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get clients", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetClient handles GET /admin/api/v1/applications/:id/clients/:clientId
+func (h *ClientHandler) GetClient(c *gin.Context) {
+	client, err := h.clientService.GetClient(c.Param("id"), c.Param("clientId"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, models.NotFoundResponse("Client not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get client", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(client))
+}
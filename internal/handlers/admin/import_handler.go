@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// maxManifestSize limits the size of uploaded import manifests
+const maxManifestSize = 10 << 20 // 10 MB
+
+// ImportHandler handles admin bulk import endpoints
+type ImportHandler struct {
+	importService *services.ImportService
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler() *ImportHandler {
+	return &ImportHandler{
+		importService: services.NewImportService(),
+	}
+}
+
+// ImportVersions handles POST /admin/api/v1/applications/:id/versions/import
+// The manifest is sent either as the raw request body or as the "manifest" file of a
+// multipart form. Query parameters: format (json, yaml, github; detected when omitted),
+// channel (default channel), dry_run and skip_existing.
+func (h *ImportHandler) ImportVersions(c *gin.Context) {
+	appID := c.Param("id")
+	format := c.Query("format")
+
+	var data []byte
+	var err error
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, header, formErr := c.Request.FormFile("manifest")
+		if formErr != nil {
+			c.JSON(http.StatusBadRequest, models.BadRequestResponse("Manifest file is required", formErr))
+			return
+		}
+		defer file.Close()
+
+		if format == "" {
+			switch strings.ToLower(filepath.Ext(header.Filename)) {
+			case ".yaml", ".yml":
+				format = models.ImportFormatYAML
+			}
+		}
+		data, err = io.ReadAll(io.LimitReader(file, maxManifestSize+1))
+	} else {
+		data, err = io.ReadAll(io.LimitReader(c.Request.Body, maxManifestSize+1))
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Failed to read manifest", err))
+		return
+	}
+	if len(data) == 0 {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Manifest is empty", nil))
+		return
+	}
+	if len(data) > maxManifestSize {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Manifest is too large", fmt.Errorf("maximum size is %d bytes", maxManifestSize)))
+		return
+	}
+
+	manifest, err := h.importService.ParseManifest(data, format, c.Query("channel"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid manifest", err))
+		return
+	}
+
+	opts := models.ImportOptions{
+		DryRun:       c.Query("dry_run") == "true",
+		SkipExisting: c.Query("skip_existing") == "true",
+	}
+
+	report, err := h.importService.Import(appID, manifest, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Failed to import versions", err))
+		return
+	}
+
+	if !report.Valid() {
+		c.JSON(http.StatusUnprocessableEntity, &models.APIResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "Manifest validation failed, nothing was imported",
+			Data:    report,
+		})
+		return
+	}
+
+	message := "Versions imported successfully"
+	if opts.DryRun {
+		message = "Manifest is valid (dry run, nothing was imported)"
+	}
+	c.JSON(http.StatusOK, models.SuccessResponseWithMessage(message, report))
+}
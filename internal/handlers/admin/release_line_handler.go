@@ -0,0 +1,94 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ReleaseLineHandler handles admin release line management endpoints
+type ReleaseLineHandler struct {
+	releaseLineService *services.ReleaseLineService
+}
+
+// NewReleaseLineHandler creates a new release line handler
+func NewReleaseLineHandler() *ReleaseLineHandler {
+	return &ReleaseLineHandler{
+		releaseLineService: services.NewReleaseLineService(),
+	}
+}
+
+// GetReleaseLines handles GET /admin/api/v1/applications/:id/release-lines
+func (h *ReleaseLineHandler) GetReleaseLines(c *gin.Context) {
+	appID := c.Param("id")
+
+	lines, err := h.releaseLineService.GetReleaseLines(appID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get release lines", err))
+		return
+	}
+
+	lineResponses := make([]*models.ReleaseLineResponse, 0, len(lines))
+	for _, line := range lines {
+		lineResponses = append(lineResponses, line.ToResponse())
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(lineResponses))
+}
+
+// GetReleaseLine handles GET /admin/api/v1/applications/:id/release-lines/:line
+func (h *ReleaseLineHandler) GetReleaseLine(c *gin.Context) {
+	line, err := h.releaseLineService.GetReleaseLine(c.Param("id"), c.Param("line"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.NotFoundResponse("Release line not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(line.ToResponse()))
+}
+
+// CreateReleaseLine handles POST /admin/api/v1/applications/:id/release-lines
+func (h *ReleaseLineHandler) CreateReleaseLine(c *gin.Context) {
+	var req models.ReleaseLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid request format", err))
+		return
+	}
+
+	line, err := h.releaseLineService.CreateReleaseLine(c.Param("id"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Failed to create release line", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse(line.ToResponse()))
+}
+
+// UpdateReleaseLine handles PUT /admin/api/v1/applications/:id/release-lines/:line
+func (h *ReleaseLineHandler) UpdateReleaseLine(c *gin.Context) {
+	var req models.ReleaseLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid request format", err))
+		return
+	}
+
+	line, err := h.releaseLineService.UpdateReleaseLine(c.Param("id"), c.Param("line"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Failed to update release line", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(line.ToResponse()))
+}
+
+// DeleteReleaseLine handles DELETE /admin/api/v1/applications/:id/release-lines/:line
+func (h *ReleaseLineHandler) DeleteReleaseLine(c *gin.Context) {
+	if err := h.releaseLineService.DeleteReleaseLine(c.Param("id"), c.Param("line")); err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Failed to delete release line", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(map[string]string{"message": "Release line deleted successfully"}))
+}
@@ -1,7 +1,12 @@
 package admin
 
 import (
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Run-Panel/VerTree/internal/models"
 	"github.com/Run-Panel/VerTree/internal/services"
@@ -22,25 +27,23 @@ func NewStatsHandler() *StatsHandler {
 
 // GetStats handles GET /admin/api/v1/stats
 func (h *StatsHandler) GetStats(c *gin.Context) {
-	var req models.StatsRequest
-
-	// Set defaults
-	req.Period = c.DefaultQuery("period", "7d")
-	req.Action = c.DefaultQuery("action", "all")
-
-	// Validate query parameters
-	if req.Period != "1d" && req.Period != "7d" && req.Period != "30d" && req.Period != "90d" {
-		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid period. Must be one of: 1d, 7d, 30d, 90d", nil))
+	req, ok := statsRequest(c)
+	if !ok {
 		return
 	}
 
+	req.Action = c.DefaultQuery("action", "all")
 	if req.Action != "all" && req.Action != "check" && req.Action != "download" && req.Action != "install" && req.Action != "success" && req.Action != "failed" {
 		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid action. Must be one of: all, check, download, install, success, failed", nil))
 		return
 	}
 
-	stats, err := h.statsService.GetStats(&req)
+	stats, err := h.statsService.GetStats(req)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, models.BadRequestResponse(err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get statistics", err))
 		return
 	}
@@ -50,17 +53,17 @@ func (h *StatsHandler) GetStats(c *gin.Context) {
 
 // GetVersionDistribution handles GET /admin/api/v1/stats/distribution
 func (h *StatsHandler) GetVersionDistribution(c *gin.Context) {
-	// Get period parameter with default
-	period := c.DefaultQuery("period", "7d")
-
-	// Validate period parameter
-	if period != "1d" && period != "7d" && period != "30d" && period != "90d" {
-		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid period. Must be one of: 1d, 7d, 30d, 90d", nil))
+	req, ok := statsRequest(c)
+	if !ok {
 		return
 	}
 
-	distribution, err := h.statsService.GetVersionDistribution(period)
+	distribution, err := h.statsService.GetVersionDistribution(req)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, models.BadRequestResponse(err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get version distribution", err))
 		return
 	}
@@ -70,20 +73,165 @@ func (h *StatsHandler) GetVersionDistribution(c *gin.Context) {
 
 // GetRegionDistribution handles GET /admin/api/v1/stats/regions
 func (h *StatsHandler) GetRegionDistribution(c *gin.Context) {
-	// Get period parameter with default
-	period := c.DefaultQuery("period", "7d")
-
-	// Validate period parameter
-	if period != "1d" && period != "7d" && period != "30d" && period != "90d" {
-		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid period. Must be one of: 1d, 7d, 30d, 90d", nil))
+	req, ok := statsRequest(c)
+	if !ok {
 		return
 	}
 
-	distribution, err := h.statsService.GetRegionDistribution(period)
+	distribution, err := h.statsService.GetRegionDistribution(req)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, models.BadRequestResponse(err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get region distribution", err))
 		return
 	}
 
 	c.JSON(http.StatusOK, models.SuccessResponse(distribution))
 }
+
+// GetFunnel handles GET /admin/api/v1/stats/funnel
+func (h *StatsHandler) GetFunnel(c *gin.Context) {
+	req, ok := statsRequest(c)
+	if !ok {
+		return
+	}
+
+	funnel, err := h.statsService.GetFunnel(req, c.Query("version"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, models.BadRequestResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get update funnel", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(funnel))
+}
+
+// GetTopInstallErrors handles GET /admin/api/v1/stats/errors
+func (h *StatsHandler) GetTopInstallErrors(c *gin.Context) {
+	req, ok := statsRequest(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid limit. Must be between 1 and 100", nil))
+		return
+	}
+
+	report, err := h.statsService.GetTopInstallErrors(req, c.Query("version"), limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, models.BadRequestResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get install errors", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(report))
+}
+
+// ExportStats handles GET /admin/api/v1/stats/export
+// The raw update stats are streamed as CSV or NDJSON (format). With a limit, the
+// X-Next-Cursor header holds the cursor query parameter of the next page.
+func (h *StatsHandler) ExportStats(c *gin.Context) {
+	req := models.StatsExportRequest{Format: models.StatsExportCSV}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid query parameters", err))
+		return
+	}
+
+	export, err := h.statsService.ExportStats(&req)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, models.BadRequestResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to export statistics", err))
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if req.Format == models.StatsExportNDJSON {
+		contentType = "application/x-ndjson"
+	}
+	if export.NextCursor > 0 {
+		c.Header("X-Next-Cursor", strconv.FormatUint(uint64(export.NextCursor), 10))
+	}
+	filename := fmt.Sprintf("update-stats-%s.%s", time.Now().UTC().Format("20060102150405"), req.Format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	// The export is streamed, so errors can't be reported to the client anymore
+	if err := export.Write(c.Writer); err != nil {
+		log.Printf("Failed to write stats export: %v", err)
+	}
+}
+
+// RollupStats handles POST /admin/api/v1/stats/rollup, it recomputes the daily rollups of
+// the from and to dates, for instance after stats were imported or corrected
+func (h *StatsHandler) RollupStats(c *gin.Context) {
+	days, err := h.statsService.RollupDays(c.Query("from"), c.Query("to"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, models.BadRequestResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to roll up statistics", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(gin.H{"days": days}))
+}
+
+// GetSupportReport handles GET /admin/api/v1/stats/support
+func (h *StatsHandler) GetSupportReport(c *gin.Context) {
+	filter := statsFilter(c)
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid days. Must be between 1 and 365", nil))
+		return
+	}
+
+	report, err := h.statsService.GetSupportReport(&filter, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get support report", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(report))
+}
+
+// statsRequest reads the period, or the from and to dates, and the filter of the stats
+// endpoints. It responds with an error and returns false if the period is invalid.
+func statsRequest(c *gin.Context) (*models.StatsRequest, bool) {
+	req := &models.StatsRequest{
+		Period:      c.DefaultQuery("period", "7d"),
+		From:        c.Query("from"),
+		To:          c.Query("to"),
+		StatsFilter: statsFilter(c),
+	}
+
+	if req.Period != "1d" && req.Period != "7d" && req.Period != "30d" && req.Period != "90d" {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid period. Must be one of: 1d, 7d, 30d, 90d", nil))
+		return nil, false
+	}
+
+	return req, true
+}
+
+// statsFilter reads the optional app_id and channel query parameters of the stats endpoints
+func statsFilter(c *gin.Context) models.StatsFilter {
+	return models.StatsFilter{
+		AppID:   c.Query("app_id"),
+		Channel: c.Query("channel"),
+	}
+}
@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// TUFHandler handles admin endpoints for the TUF metadata and signing keys of applications
+type TUFHandler struct {
+	tufService *services.TUFService
+}
+
+// NewTUFHandler creates a new TUF handler
+func NewTUFHandler() *TUFHandler {
+	return &TUFHandler{
+		tufService: services.NewTUFService(),
+	}
+}
+
+// GetStatus handles GET /admin/api/v1/applications/:id/tuf
+func (h *TUFHandler) GetStatus(c *gin.Context) {
+	status, err := h.tufService.GetStatus(c.Param("id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, models.NotFoundResponse("Application not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get TUF status", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(status))
+}
+
+// Regenerate handles POST /admin/api/v1/applications/:id/tuf/regenerate
+func (h *TUFHandler) Regenerate(c *gin.Context) {
+	appID := c.Param("id")
+
+	if err := h.tufService.Regenerate(appID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, models.NotFoundResponse("Application not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to regenerate TUF metadata", err))
+		return
+	}
+
+	status, err := h.tufService.GetStatus(appID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get TUF status", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponseWithMessage("TUF metadata regenerated successfully", status))
+}
+
+// RotateKey handles POST /admin/api/v1/applications/:id/tuf/keys/:role/rotate
+func (h *TUFHandler) RotateKey(c *gin.Context) {
+	role := c.Param("role")
+	if !models.IsValidTUFRole(role) {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid TUF role", nil))
+		return
+	}
+
+	key, err := h.tufService.RotateKey(c.Param("id"), role)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, models.NotFoundResponse("Application not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to rotate TUF key", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponseWithMessage("TUF key rotated successfully", key.ToResponse()))
+}
@@ -0,0 +1,50 @@
+package admin
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateExplainHandler handles the admin explanation of update decisions
+type UpdateExplainHandler struct {
+	updateService *services.UpdateService
+}
+
+// NewUpdateExplainHandler creates a new update explain handler
+func NewUpdateExplainHandler() *UpdateExplainHandler {
+	return &UpdateExplainHandler{
+		updateService: services.NewUpdateService(),
+	}
+}
+
+// ExplainUpdate handles POST /admin/api/v1/applications/:id/explain-update
+func (h *UpdateExplainHandler) ExplainUpdate(c *gin.Context) {
+	var req models.ExplainUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid request format", err))
+		return
+	}
+	if req.APIVersion != "" && req.APIVersion != "v1" && req.APIVersion != "v2" {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid api_version, use v1 or v2", nil))
+		return
+	}
+
+	explanation, err := h.updateService.ExplainUpdate(c.Param("id"), &req)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "application not found"):
+			c.JSON(http.StatusNotFound, models.NotFoundResponse("Application not found"))
+		case strings.Contains(err.Error(), "required"):
+			c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid request", err))
+		default:
+			c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to explain update decision", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(explanation))
+}
@@ -2,6 +2,7 @@ package admin
 
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -158,6 +159,32 @@ func (h *VersionHandler) PublishVersion(c *gin.Context) {
 	c.JSON(http.StatusOK, models.SuccessResponse(version.ToResponse()))
 }
 
+// ForceUpdate handles POST /admin/api/v1/versions/:id/force-update
+func (h *VersionHandler) ForceUpdate(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid version ID", err))
+		return
+	}
+
+	var req models.ForceUpdateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid request format", err))
+			return
+		}
+	}
+
+	version, err := h.versionService.ForceUpdate(uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Failed to force update", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(version.ToResponse()))
+}
+
 // UnpublishVersion handles POST /admin/api/v1/versions/:id/unpublish
 func (h *VersionHandler) UnpublishVersion(c *gin.Context) {
 	idStr := c.Param("id")
@@ -176,6 +203,30 @@ func (h *VersionHandler) UnpublishVersion(c *gin.Context) {
 	c.JSON(http.StatusOK, models.SuccessResponse(version.ToResponse()))
 }
 
+// UpdateSupportWindow handles PUT /admin/api/v1/versions/:id/support-window
+func (h *VersionHandler) UpdateSupportWindow(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid version ID", err))
+		return
+	}
+
+	var req models.SupportWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid request format", err))
+		return
+	}
+
+	version, err := h.versionService.UpdateSupportWindow(uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Failed to update support window", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(version.ToResponse()))
+}
+
 // DeleteVersion handles DELETE /admin/api/v1/versions/:id
 func (h *VersionHandler) DeleteVersion(c *gin.Context) {
 	idStr := c.Param("id")
@@ -193,6 +244,117 @@ func (h *VersionHandler) DeleteVersion(c *gin.Context) {
 	c.JSON(http.StatusOK, models.SuccessResponse(map[string]string{"message": "Version deleted successfully"}))
 }
 
+// GetLocalizations handles GET /admin/api/v1/versions/:id/localizations
+func (h *VersionHandler) GetLocalizations(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid version ID", err))
+		return
+	}
+
+	localizations, err := h.versionService.GetLocalizations(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.NotFoundResponse("Version not found"))
+		return
+	}
+
+	var responses []*models.VersionLocalizationResponse
+	for _, localization := range localizations {
+		responses = append(responses, localization.ToResponse())
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(responses))
+}
+
+// UpsertLocalization handles PUT /admin/api/v1/versions/:id/localizations/:locale
+func (h *VersionHandler) UpsertLocalization(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid version ID", err))
+		return
+	}
+
+	var req models.VersionLocalizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid request format", err))
+		return
+	}
+
+	localization, err := h.versionService.UpsertLocalization(uint(id), c.Param("locale"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Failed to save localization", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(localization.ToResponse()))
+}
+
+// DeleteLocalization handles DELETE /admin/api/v1/versions/:id/localizations/:locale
+func (h *VersionHandler) DeleteLocalization(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid version ID", err))
+		return
+	}
+
+	if err := h.versionService.DeleteLocalization(uint(id), c.Param("locale")); err != nil {
+		c.JSON(http.StatusNotFound, models.NotFoundResponse("Localization not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(map[string]string{"message": "Localization deleted successfully"}))
+}
+
+// UpsertArtifact handles PUT /admin/api/v1/versions/:id/artifacts
+func (h *VersionHandler) UpsertArtifact(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid version ID", err))
+		return
+	}
+
+	var req models.VersionArtifactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid request format", err))
+		return
+	}
+
+	artifact, err := h.versionService.UpsertArtifact(uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Failed to save artifact", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(artifact.ToResponse()))
+}
+
+// DeleteArtifact handles DELETE /admin/api/v1/versions/:id/artifacts/:artifact_id
+func (h *VersionHandler) DeleteArtifact(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid version ID", err))
+		return
+	}
+
+	artifactID, err := strconv.ParseUint(c.Param("artifact_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid artifact ID", err))
+		return
+	}
+
+	if err := h.versionService.DeleteArtifact(uint(id), uint(artifactID)); err != nil {
+		c.JSON(http.StatusNotFound, models.NotFoundResponse("Artifact not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(map[string]string{"message": "Artifact deleted successfully"}))
+}
+
 // CreateVersionWithUpload handles POST /admin/api/v1/applications/:id/versions/upload
 func (h *VersionHandler) CreateVersionWithUpload(c *gin.Context) {
 	// Get app_id from URL path parameter
@@ -269,6 +431,14 @@ func (h *VersionHandler) CreateVersionWithUpload(c *gin.Context) {
 		FileSize:          size,
 		FileChecksum:      checksum,
 		IsForced:          c.PostForm("is_forced") == "true",
+		MinOSVersion:      c.PostForm("min_os_version"),
+		MaxOSVersion:      c.PostForm("max_os_version"),
+	}
+
+	if err := parseConstraintForm(c, &req); err != nil {
+		os.Remove(filepath) // Clean up on error
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid compatibility constraints", err))
+		return
 	}
 
 	// Validate required fields (app_id is already validated from URL)
@@ -346,6 +516,16 @@ func (h *VersionHandler) UpdateVersionWithUpload(c *gin.Context) {
 		FileSize:          existingVersion.FileSize,
 		FileChecksum:      existingVersion.FileChecksum,
 		IsForced:          c.PostForm("is_forced") == "true",
+
+		MinOSVersion:         getFormValueOrKeep(c, "min_os_version", existingVersion.MinOSVersion),
+		MaxOSVersion:         getFormValueOrKeep(c, "max_os_version", existingVersion.MaxOSVersion),
+		RequiredDependencies: existingVersion.RequiredDependencies,
+		RequiredCapabilities: existingVersion.RequiredCapabilities,
+	}
+
+	if err := parseConstraintForm(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid compatibility constraints", err))
+		return
 	}
 
 	// Check if new file is uploaded
@@ -447,6 +627,33 @@ func currentTimeMillis() int64 {
 	return time.Now().UnixNano() / int64(time.Millisecond)
 }
 
+// parseConstraintForm reads the list-valued compatibility constraints from multipart form data.
+// required_dependencies is a JSON array, required_capabilities is comma separated.
+// Fields that are not present keep the values already set on the request, empty ones clear them.
+func parseConstraintForm(c *gin.Context, req *models.VersionRequest) error {
+	if value, ok := c.GetPostForm("required_dependencies"); ok {
+		var deps models.DependencyList
+		if value != "" {
+			if err := json.Unmarshal([]byte(value), &deps); err != nil {
+				return fmt.Errorf("required_dependencies must be a JSON array: %w", err)
+			}
+		}
+		req.RequiredDependencies = deps
+	}
+
+	if value, ok := c.GetPostForm("required_capabilities"); ok {
+		var capabilities models.StringList
+		for _, capability := range strings.Split(value, ",") {
+			if capability = strings.TrimSpace(capability); capability != "" {
+				capabilities = append(capabilities, capability)
+			}
+		}
+		req.RequiredCapabilities = capabilities
+	}
+
+	return nil
+}
+
 func getFormValueOrDefault(c *gin.Context, key, defaultValue string) string {
 	if value := c.PostForm(key); value != "" {
 		return value
@@ -454,6 +661,15 @@ func getFormValueOrDefault(c *gin.Context, key, defaultValue string) string {
 	return defaultValue
 }
 
+// getFormValueOrKeep returns a form value if the field is present, even if empty so an
+// optional setting can be cleared, and the current value otherwise
+func getFormValueOrKeep(c *gin.Context, key, current string) string {
+	if value, ok := c.GetPostForm(key); ok {
+		return value
+	}
+	return current
+}
+
 // CreateVersionWithUploadGlobal creates a new version with file upload (global endpoint)
 func (h *VersionHandler) CreateVersionWithUploadGlobal(c *gin.Context) {
 	// Get app_id from form data since it's not in the URL
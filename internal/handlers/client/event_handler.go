@@ -0,0 +1,114 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// eventStreamHeartbeat keeps idle connections open through proxies
+	eventStreamHeartbeat = 25 * time.Second
+	// eventStreamRetry is the reconnect delay suggested to clients, in milliseconds
+	eventStreamRetry = 5000
+)
+
+// EventHandler streams release events to clients using Server-Sent Events
+type EventHandler struct {
+	eventService   *services.EventService
+	channelService *services.ChannelService
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler() *EventHandler {
+	return &EventHandler{
+		eventService:   services.NewEventService(),
+		channelService: services.NewChannelService(),
+	}
+}
+
+// Stream handles GET /api/v1/events
+// The optional channel query parameter limits events to one channel. Reconnecting clients
+// send the Last-Event-ID header (or last_event_id query parameter) to receive missed events.
+func (h *EventHandler) Stream(c *gin.Context) {
+	appID := c.GetString("app_id")
+
+	channel := c.Query("channel")
+	if channel != "" {
+		if err := h.channelService.ValidateChannelForApp(appID, channel); err != nil {
+			c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid channel", err))
+			return
+		}
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var lastID uint64
+	if lastEventID != "" {
+		parsed, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid Last-Event-ID", err))
+			return
+		}
+		lastID = parsed
+	}
+
+	sub, replay := h.eventService.Subscribe(appID, channel, lastID)
+	defer h.eventService.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", eventStreamRetry)
+	for _, event := range replay {
+		if err := writeReleaseEvent(c, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Subscription ended (slow client or shutdown); the client reconnects and replays
+				return
+			}
+			if err := writeReleaseEvent(c, event); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeReleaseEvent writes a release event in the SSE wire format
+func writeReleaseEvent(c *gin.Context, event *models.ReleaseEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
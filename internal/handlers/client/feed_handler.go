@@ -0,0 +1,149 @@
+package client
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html/template"
+	"net/http"
+	"net/url"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// releaseNotesTemplate renders the release notes page linked from update feeds
+var releaseNotesTemplate = template.Must(template.New("release-notes").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Version.Title}}</title>
+</head>
+<body>
+<h1>{{.Version.Title}}</h1>
+{{if .Version.Description}}<p>{{.Version.Description}}</p>{{end}}
+{{if .Version.ReleaseNotes}}<div style="white-space: pre-wrap">{{.Version.ReleaseNotes}}</div>{{end}}
+{{if .Version.BreakingChanges}}<h2>Breaking changes</h2>
+<div style="white-space: pre-wrap">{{.Version.BreakingChanges}}</div>{{end}}
+</body>
+</html>
+`))
+
+// FeedHandler handles update feeds for third-party updater frameworks
+type FeedHandler struct {
+	feedService    *services.FeedService
+	versionService *services.VersionService
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler() *FeedHandler {
+	return &FeedHandler{
+		feedService:    services.NewFeedService(),
+		versionService: services.NewVersionService(),
+	}
+}
+
+// feedLinks builds the links of a feed from the request, passing the feed token along
+// so that Sparkle can follow them without an Authorization header
+func feedLinks(c *gin.Context) services.FeedLinks {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	links := services.FeedLinks{BaseURL: scheme + "://" + c.Request.Host}
+	if token, ok := c.Get("feed_token"); ok {
+		links.Query = url.Values{"token": {token.(string)}}
+	}
+	return links
+}
+
+// Appcast handles GET /feeds/:app_id/:channel/appcast.xml
+func (h *FeedHandler) Appcast(c *gin.Context) {
+	app := c.MustGet("app").(*models.Application)
+
+	appcast, err := h.feedService.BuildAppcast(app, c.Param("channel"), feedLinks(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.NotFoundResponse(err.Error()))
+		return
+	}
+
+	output, err := xml.MarshalIndent(appcast, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to render appcast", err))
+		return
+	}
+
+	c.Data(http.StatusOK, models.AppcastContentType, append([]byte(xml.Header), output...))
+}
+
+// ElectronManifest handles GET /feeds/:app_id/electron/:file
+// The file is the manifest requested by electron-updater's generic provider, e.g. latest.yml,
+// latest-mac.yml or beta-linux.yml.
+func (h *FeedHandler) ElectronManifest(c *gin.Context) {
+	channel, os, arch, err := services.ParseElectronManifestName(c.Param("file"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.NotFoundResponse(err.Error()))
+		return
+	}
+
+	info, err := h.feedService.BuildElectronManifest(c.GetString("app_id"), channel, os, arch, feedLinks(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.NotFoundResponse(err.Error()))
+		return
+	}
+
+	output, err := yaml.Marshal(info)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to render manifest", err))
+		return
+	}
+
+	c.Data(http.StatusOK, models.ElectronManifestContentType, output)
+}
+
+// SquirrelReleases handles GET /feeds/:app_id/squirrel/:channel/RELEASES
+// Squirrel.Windows passes the architecture of the installed app in the arch query parameter.
+func (h *FeedHandler) SquirrelReleases(c *gin.Context) {
+	releases, err := h.feedService.BuildSquirrelReleases(c.GetString("app_id"), c.Param("channel"), c.Query("arch"), c.ClientIP(), feedLinks(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.NotFoundResponse(err.Error()))
+		return
+	}
+
+	c.String(http.StatusOK, releases)
+}
+
+// ReleaseNotes handles GET /feeds/:app_id/release-notes/:version
+// The lang query parameter (or Accept-Language header) selects the localized texts.
+func (h *FeedHandler) ReleaseNotes(c *gin.Context) {
+	appID := c.GetString("app_id")
+
+	version, err := h.versionService.GetVersionByNumber(appID, c.Param("version"))
+	if err != nil || !version.IsPublished {
+		c.JSON(http.StatusNotFound, models.NotFoundResponse("Version not found"))
+		return
+	}
+
+	lang := c.Query("lang")
+	if lang == "" {
+		lang = c.GetHeader("Accept-Language")
+	}
+	applied, err := h.versionService.LocalizeVersions([]*models.Version{version}, lang)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to localize release notes", err))
+		return
+	}
+
+	var page bytes.Buffer
+	if err := releaseNotesTemplate.Execute(&page, map[string]interface{}{
+		"Lang":    applied[version.ID],
+		"Version": version,
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to render release notes", err))
+		return
+	}
+
+	c.Data(http.StatusOK, models.ReleaseNotesMediaType, page.Bytes())
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// TUFHandler serves the TUF repository metadata of the authenticated application
+type TUFHandler struct {
+	tufService *services.TUFService
+}
+
+// NewTUFHandler creates a new TUF handler
+func NewTUFHandler() *TUFHandler {
+	return &TUFHandler{
+		tufService: services.NewTUFService(),
+	}
+}
+
+// GetMetadata handles GET /api/v1/tuf/:file
+// file is root.json, targets.json, snapshot.json, timestamp.json or a versioned root (N.root.json).
+func (h *TUFHandler) GetMetadata(c *gin.Context) {
+	metadata, err := h.tufService.GetMetadata(c.GetString("app_id"), c.Param("file"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, models.NotFoundResponse("Metadata not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get TUF metadata", err))
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Data(http.StatusOK, "application/json; charset=utf-8", metadata)
+}
@@ -1,9 +1,11 @@
 package client
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/Run-Panel/VerTree/internal/middleware"
 	"github.com/Run-Panel/VerTree/internal/models"
 	"github.com/Run-Panel/VerTree/internal/services"
 	"github.com/gin-gonic/gin"
@@ -31,6 +33,16 @@ func (h *UpdateHandler) CheckUpdate(c *gin.Context) {
 		return
 	}
 
+	// Anonymous requests are limited to the public application they were admitted for
+	if c.GetBool("anonymous") {
+		req.AppID = c.GetString("app_id")
+	}
+
+	// Fall back to the Accept-Language header when no locale is given in the body
+	if req.Locale == "" {
+		req.Locale = c.GetHeader("Accept-Language")
+	}
+
 	// Get client IP
 	clientIP := c.ClientIP()
 	if forwardedFor := c.GetHeader("X-Forwarded-For"); forwardedFor != "" {
@@ -46,6 +58,53 @@ func (h *UpdateHandler) CheckUpdate(c *gin.Context) {
 	c.JSON(http.StatusOK, models.SuccessResponse(response))
 }
 
+// CheckUpdateV2 handles POST /api/v2/check-update. Unlike v1, errors carry a stable error
+// code, and the reason is given when no update is offered.
+func (h *UpdateHandler) CheckUpdateV2(c *gin.Context) {
+	var req models.CheckUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondError(c, models.BadRequestResponse("Invalid request format", err), models.ErrCodeInvalidRequest)
+		return
+	}
+	if req.CurrentVersion == "" || req.Channel == "" || req.ClientID == "" {
+		middleware.RespondError(c, models.BadRequestResponse("current_version, channel and client_id are required", nil), models.ErrCodeInvalidRequest)
+		return
+	}
+
+	// The app_id defaults to the authenticated application and must match it
+	appID := c.GetString("app_id")
+	if req.AppID == "" {
+		req.AppID = appID
+	} else if req.AppID != appID {
+		middleware.RespondError(c, models.ForbiddenResponse("app_id does not match the API key"), models.ErrCodeAppMismatch)
+		return
+	}
+
+	// Fall back to the Accept-Language header when no locale is given in the body
+	if req.Locale == "" {
+		req.Locale = c.GetHeader("Accept-Language")
+	}
+
+	// Get client IP
+	clientIP := c.ClientIP()
+	if forwardedFor := c.GetHeader("X-Forwarded-For"); forwardedFor != "" {
+		clientIP = forwardedFor
+	}
+
+	decision, err := h.updateService.CheckUpdateV2(&req, clientIP)
+	if err != nil {
+		var clientErr *models.ClientError
+		if errors.As(err, &clientErr) {
+			middleware.RespondError(c, models.ErrorResponseWithCode(clientErr.Status, clientErr.Message, nil), clientErr.Code)
+			return
+		}
+		middleware.RespondError(c, models.InternalServerErrorResponse("Failed to check for updates", err), models.ErrCodeInternal)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(decision.ToResponseV2()))
+}
+
 // DownloadStarted handles POST /api/v1/download-started
 func (h *UpdateHandler) DownloadStarted(c *gin.Context) {
 	var req struct {
@@ -54,7 +113,7 @@ func (h *UpdateHandler) DownloadStarted(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid request format", err))
+		middleware.RespondError(c, models.BadRequestResponse("Invalid request format", err), models.ErrCodeInvalidRequest)
 		return
 	}
 
@@ -64,7 +123,7 @@ func (h *UpdateHandler) DownloadStarted(c *gin.Context) {
 		clientIP = forwardedFor
 	}
 
-	if err := h.updateService.RecordDownloadStart(req.Version, req.ClientID, clientIP); err != nil {
+	if err := h.updateService.RecordDownloadStart(c.GetString("app_id"), req.Version, req.ClientID, clientIP); err != nil {
 		// Don't fail the request if logging fails
 		// Just log the error and continue
 	}
@@ -82,7 +141,7 @@ func (h *UpdateHandler) InstallResult(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.BadRequestResponse("Invalid request format", err))
+		middleware.RespondError(c, models.BadRequestResponse("Invalid request format", err), models.ErrCodeInvalidRequest)
 		return
 	}
 
@@ -92,7 +151,7 @@ func (h *UpdateHandler) InstallResult(c *gin.Context) {
 		clientIP = forwardedFor
 	}
 
-	if err := h.updateService.RecordInstallResult(req.Version, req.ClientID, req.Success, req.ErrorMessage, clientIP); err != nil {
+	if err := h.updateService.RecordInstallResult(c.GetString("app_id"), req.Version, req.ClientID, req.Success, req.ErrorMessage, clientIP); err != nil {
 		// Don't fail the request if logging fails
 		// Just log the error and continue
 	}
@@ -100,12 +159,49 @@ func (h *UpdateHandler) InstallResult(c *gin.Context) {
 	c.JSON(http.StatusOK, models.SuccessResponse(map[string]string{"message": "Install result recorded"}))
 }
 
+// TelemetryBatch handles POST /api/v1/telemetry/batch
+func (h *UpdateHandler) TelemetryBatch(c *gin.Context) {
+	var req models.TelemetryBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondError(c, models.BadRequestResponse("Invalid request format", err), models.ErrCodeInvalidRequest)
+		return
+	}
+
+	// Each event type requires the permission of its single event endpoint
+	permissions, _ := c.Get("api_key_permissions")
+	keyPermissions, _ := permissions.(models.PermissionsList)
+	for _, event := range req.Events {
+		if event.ClientID == "" && req.ClientID == "" {
+			middleware.RespondError(c, models.BadRequestResponse("client_id is required for event "+event.EventID, nil), models.ErrCodeInvalidRequest)
+			return
+		}
+		if !keyPermissions.Has(event.Type) {
+			middleware.RespondError(c, models.ErrorResponseWithCode(403, "Insufficient permissions for "+event.Type+" events", nil), models.ErrCodePermissionDenied)
+			return
+		}
+	}
+
+	// Get client IP
+	clientIP := c.ClientIP()
+	if forwardedFor := c.GetHeader("X-Forwarded-For"); forwardedFor != "" {
+		clientIP = forwardedFor
+	}
+
+	response, err := h.updateService.RecordTelemetryBatch(c.GetString("app_id"), &req, clientIP)
+	if err != nil {
+		middleware.RespondError(c, models.InternalServerErrorResponse("Failed to record telemetry events", err), models.ErrCodeInternal)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(response))
+}
+
 // GetVersions handles GET /api/v1/versions
 func (h *UpdateHandler) GetVersions(c *gin.Context) {
 	// Get app_id from middleware (set by API key authentication)
 	appID, exists := c.Get("app_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.UnauthorizedResponse("Application ID not found"))
+		middleware.RespondError(c, models.UnauthorizedResponse("Application ID not found"), models.ErrCodeMissingCredentials)
 		return
 	}
 
@@ -120,11 +216,26 @@ func (h *UpdateHandler) GetVersions(c *gin.Context) {
 	}
 
 	publishedOnly := publishedOnlyStr != "false" // Default to true unless explicitly false
+	if c.GetBool("anonymous") {
+		publishedOnly = true // Unpublished versions are only visible with an API key
+	}
+
+	locale := c.Query("locale")
+	if locale == "" {
+		locale = c.GetHeader("Accept-Language")
+	}
 
 	// Get versions for this app
 	versions, err := h.versionService.GetVersionsForApp(appID.(string), channel, limit, publishedOnly)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.InternalServerErrorResponse("Failed to get versions", err))
+		middleware.RespondError(c, models.InternalServerErrorResponse("Failed to get versions", err), models.ErrCodeInternal)
+		return
+	}
+
+	// Pick the release texts matching the client's language
+	applied, err := h.versionService.LocalizeVersions(versions, locale)
+	if err != nil {
+		middleware.RespondError(c, models.InternalServerErrorResponse("Failed to localize versions", err), models.ErrCodeInternal)
 		return
 	}
 
@@ -144,6 +255,10 @@ func (h *UpdateHandler) GetVersions(c *gin.Context) {
 			"min_upgrade_version": version.MinUpgradeVersion,
 		}
 
+		if l, ok := applied[version.ID]; ok {
+			versionResponse["locale"] = l
+		}
+
 		// Add published_at only if published
 		if version.IsPublished && version.PublishTime != nil {
 			versionResponse["published_at"] = version.PublishTime
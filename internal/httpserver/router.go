@@ -0,0 +1,292 @@
+// Package httpserver sets up the routes of the VerTree HTTP API.
+package httpserver
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Run-Panel/VerTree/internal/config"
+	"github.com/Run-Panel/VerTree/internal/handlers/admin"
+	"github.com/Run-Panel/VerTree/internal/handlers/auth"
+	"github.com/Run-Panel/VerTree/internal/handlers/client"
+	"github.com/Run-Panel/VerTree/internal/middleware"
+	"github.com/Run-Panel/VerTree/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewRouter creates the HTTP router serving the admin, auth and client APIs, the update
+// feeds and the admin frontend. rateLimiters are the limiters created by
+// middleware.CreateRateLimiters, which the gRPC API shares.
+func NewRouter(cfg *config.Config, rateLimiters map[string]*middleware.RateLimiter) *gin.Engine {
+	router := gin.New()
+
+	// Create JWT manager
+	jwtManager := utils.NewJWTManager(cfg.App.JWTSecret)
+
+	// Global middleware (applied to all routes)
+	router.Use(middleware.Logger())
+	router.Use(middleware.Metrics())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.SecurityHeaders())
+	router.Use(middleware.XSSProtection())
+	router.Use(middleware.SQLInjectionProtection())
+	router.Use(middleware.CORS())
+	router.Use(gin.Recovery())
+
+	// Public health check endpoint (no auth required)
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "ok",
+			"service": "VerTree-Service",
+			"version": "1.0.0",
+			"region":  cfg.App.Region,
+		})
+	})
+
+	// Prometheus metrics, protected by METRICS_TOKEN if it is set. Production servers only
+	// serve them with a token, the metrics name the applications and their traffic.
+	switch {
+	case cfg.Server.MetricsToken != "":
+		router.GET("/metrics", middleware.MetricsAuth(cfg.Server.MetricsToken), gin.WrapH(promhttp.Handler()))
+	case cfg.App.Environment == "production":
+		log.Printf("Warning: METRICS_TOKEN is not set, /metrics is disabled in production")
+	default:
+		log.Printf("Warning: METRICS_TOKEN is not set, /metrics is served without authentication")
+		router.GET("/metrics", middleware.MetricsAuth(""), gin.WrapH(promhttp.Handler()))
+	}
+
+	// Initialize handlers
+	authHandler := auth.NewAuthHandler(cfg.App.JWTSecret)
+	applicationHandler := admin.NewApplicationHandler()
+	versionHandler := admin.NewVersionHandler()
+	channelHandler := admin.NewChannelHandler()
+	releaseLineHandler := admin.NewReleaseLineHandler()
+	importHandler := admin.NewImportHandler()
+	bundleHandler := admin.NewBundleHandler()
+	tufHandler := admin.NewTUFHandler()
+	clientHandler := admin.NewClientHandler()
+	updateExplainHandler := admin.NewUpdateExplainHandler()
+	statsHandler := admin.NewStatsHandler()
+	apiDocsHandler := admin.NewAPIDocsHandler()
+	updateHandler := client.NewUpdateHandler()
+	feedHandler := client.NewFeedHandler()
+	clientTUFHandler := client.NewTUFHandler()
+	eventHandler := client.NewEventHandler()
+
+	// Auth API routes (public endpoints with rate limiting)
+	authGroup := router.Group("/auth/api/v1")
+	authGroup.Use(middleware.RateLimitByType(rateLimiters, "auth"))
+	{
+		authGroup.POST("/login", authHandler.Login)
+		authGroup.POST("/refresh", authHandler.RefreshToken)
+		authGroup.POST("/logout", authHandler.Logout)
+	}
+
+	// Admin API routes (protected with authentication and admin permissions)
+	adminV1 := router.Group("/admin/api/v1")
+	adminV1.Use(middleware.RateLimitByType(rateLimiters, "admin"))
+	adminV1.Use(middleware.AuthMiddleware(jwtManager))
+	adminV1.Use(middleware.RequireAdmin()) // Requires admin or superadmin role
+	{
+		// User profile management (authenticated users)
+		adminV1.GET("/profile", authHandler.GetProfile)
+		adminV1.POST("/change-password", authHandler.ChangePassword)
+
+		// Application management
+		applications := adminV1.Group("/applications")
+		{
+			applications.GET("", applicationHandler.GetApplications)
+			applications.GET("/:id", applicationHandler.GetApplication)
+			applications.POST("", applicationHandler.CreateApplication)
+			applications.PUT("/:id", applicationHandler.UpdateApplication)
+			applications.DELETE("/:id", applicationHandler.DeleteApplication)
+
+			// Application export/import bundles for moving apps between instances
+			applications.GET("/:id/export", bundleHandler.ExportApplication)
+			applications.POST("/import", bundleHandler.ImportApplication)
+
+			// Application API keys management
+			applications.GET("/:id/keys", applicationHandler.GetApplicationKeys)
+			applications.POST("/:id/keys", applicationHandler.CreateApplicationKey)
+			applications.PUT("/:id/keys/:keyId", applicationHandler.UpdateApplicationKey)
+			applications.DELETE("/:id/keys/:keyId", applicationHandler.DeleteApplicationKey)
+			applications.POST("/:id/feed-token", applicationHandler.RotateFeedToken)
+
+			// Application-specific channel management
+			applications.GET("/:id/channels", channelHandler.GetChannelsByApp)
+			applications.GET("/:id/channels/all", channelHandler.GetAllChannelsForApp)
+			applications.PUT("/:id/channels/:channel", channelHandler.EnableChannelForApp)
+			applications.DELETE("/:id/channels/:channel", channelHandler.DisableChannelForApp)
+
+			// Application release lines (LTS tracks)
+			applications.GET("/:id/release-lines", releaseLineHandler.GetReleaseLines)
+			applications.POST("/:id/release-lines", releaseLineHandler.CreateReleaseLine)
+			applications.GET("/:id/release-lines/:line", releaseLineHandler.GetReleaseLine)
+			applications.PUT("/:id/release-lines/:line", releaseLineHandler.UpdateReleaseLine)
+			applications.DELETE("/:id/release-lines/:line", releaseLineHandler.DeleteReleaseLine)
+
+			// TUF repository metadata and signing keys
+			applications.GET("/:id/tuf", tufHandler.GetStatus)
+			applications.POST("/:id/tuf/regenerate", tufHandler.Regenerate)
+			applications.POST("/:id/tuf/keys/:role/rotate", tufHandler.RotateKey)
+
+			// Client inventory
+			applications.GET("/:id/clients", clientHandler.GetClients)
+			applications.GET("/:id/clients/:clientId", clientHandler.GetClient)
+
+			// Dry run of the update decision for a simulated update check
+			applications.POST("/:id/explain-update", updateExplainHandler.ExplainUpdate)
+
+			// Application-specific version management - 使用相同的参数名 :id
+			appVersions := applications.Group("/:id/versions")
+			{
+				appVersions.POST("/import", importHandler.ImportVersions)
+				appVersions.POST("/upload", versionHandler.CreateVersionWithUpload)
+				appVersions.PUT("/:version_id/upload", versionHandler.UpdateVersionWithUpload)
+			}
+		}
+
+		// Global version management (cross-application)
+		versions := adminV1.Group("/versions")
+		{
+			versions.POST("", versionHandler.CreateVersion)
+			versions.POST("/upload", versionHandler.CreateVersionWithUploadGlobal)
+			versions.GET("", versionHandler.GetVersions)
+			versions.GET("/:id", versionHandler.GetVersion)
+			versions.PUT("/:id", versionHandler.UpdateVersion)
+			versions.PUT("/:id/upload", versionHandler.UpdateVersionWithUploadGlobal)
+			versions.DELETE("/:id", versionHandler.DeleteVersion)
+			versions.POST("/:id/publish", versionHandler.PublishVersion)
+			versions.POST("/:id/unpublish", versionHandler.UnpublishVersion)
+			versions.POST("/:id/force-update", versionHandler.ForceUpdate)
+			versions.PUT("/:id/support-window", versionHandler.UpdateSupportWindow)
+
+			// Platform-specific artifacts
+			versions.PUT("/:id/artifacts", versionHandler.UpsertArtifact)
+			versions.DELETE("/:id/artifacts/:artifact_id", versionHandler.DeleteArtifact)
+
+			// Localized release information
+			versions.GET("/:id/localizations", versionHandler.GetLocalizations)
+			versions.PUT("/:id/localizations/:locale", versionHandler.UpsertLocalization)
+			versions.DELETE("/:id/localizations/:locale", versionHandler.DeleteLocalization)
+		}
+
+		// Channel management
+		channels := adminV1.Group("/channels")
+		{
+			channels.GET("", channelHandler.GetChannels)
+			channels.GET("/:id", channelHandler.GetChannel)
+			channels.POST("", channelHandler.CreateChannel)
+			channels.PUT("/:id", channelHandler.UpdateChannel)
+			channels.DELETE("/:id", channelHandler.DeleteChannel)
+		}
+
+		// Statistics
+		adminV1.GET("/stats", statsHandler.GetStats)
+		adminV1.GET("/stats/distribution", statsHandler.GetVersionDistribution)
+		adminV1.GET("/stats/regions", statsHandler.GetRegionDistribution)
+		adminV1.GET("/stats/support", statsHandler.GetSupportReport)
+		adminV1.GET("/stats/funnel", statsHandler.GetFunnel)
+		adminV1.GET("/stats/errors", statsHandler.GetTopInstallErrors)
+		adminV1.GET("/stats/export", statsHandler.ExportStats)
+		adminV1.POST("/stats/rollup", statsHandler.RollupStats)
+
+		// API Documentation
+		adminV1.GET("/docs", apiDocsHandler.GetAPIDocs)
+
+		// Admin management (superadmin only)
+		admins := adminV1.Group("/admins")
+		admins.Use(middleware.RequireSuperAdmin()) // Requires superadmin role
+		{
+			admins.GET("", authHandler.ListAdmins)
+			admins.GET("/:id", authHandler.GetAdmin)
+			admins.POST("", authHandler.CreateAdmin)
+			admins.PUT("/:id", authHandler.UpdateAdmin)
+			admins.DELETE("/:id", authHandler.DeleteAdmin)
+		}
+	}
+
+	// Client update checks (API key authentication, or anonymous for public applications)
+	publicV1 := router.Group("/api/v1")
+	publicV1.Use(middleware.RateLimitByType(rateLimiters, "client"))
+	publicV1.Use(middleware.OptionalAPIKeyAuth())
+	publicV1.Use(middleware.PublicAppAccess(rateLimiters["public"]))
+	{
+		publicV1.POST("/check-update", middleware.RequirePermission("check_update"), updateHandler.CheckUpdate)
+		publicV1.GET("/versions", middleware.RequirePermission("check_update"), updateHandler.GetVersions)
+	}
+
+	// Client API routes (public with rate limiting and API key authentication)
+	clientV1 := router.Group("/api/v1")
+	clientV1.Use(middleware.RateLimitByType(rateLimiters, "client"))
+	clientV1.Use(middleware.APIKeyAuth()) // Require API key authentication
+	{
+		clientV1.POST("/download-started", middleware.RequirePermission("download"), updateHandler.DownloadStarted)
+		clientV1.POST("/install-result", middleware.RequirePermission("install"), updateHandler.InstallResult)
+		clientV1.POST("/telemetry/batch", updateHandler.TelemetryBatch)
+		clientV1.GET("/tuf/:file", middleware.RequirePermission("check_update"), clientTUFHandler.GetMetadata)
+		clientV1.GET("/events", middleware.RequirePermission("check_update"), eventHandler.Stream)
+	}
+
+	// Client API v2: typed error codes and the reasons why no update is offered
+	publicV2 := router.Group("/api/v2")
+	publicV2.Use(middleware.TypedErrors())
+	publicV2.Use(middleware.RateLimitByType(rateLimiters, "client"))
+	publicV2.Use(middleware.OptionalAPIKeyAuth())
+	publicV2.Use(middleware.PublicAppAccess(rateLimiters["public"]))
+	{
+		publicV2.POST("/check-update", middleware.RequirePermission("check_update"), updateHandler.CheckUpdateV2)
+		publicV2.GET("/versions", middleware.RequirePermission("check_update"), updateHandler.GetVersions)
+	}
+
+	clientV2 := router.Group("/api/v2")
+	clientV2.Use(middleware.TypedErrors())
+	clientV2.Use(middleware.RateLimitByType(rateLimiters, "client"))
+	clientV2.Use(middleware.APIKeyAuth())
+	{
+		clientV2.POST("/download-started", middleware.RequirePermission("download"), updateHandler.DownloadStarted)
+		clientV2.POST("/install-result", middleware.RequirePermission("install"), updateHandler.InstallResult)
+		clientV2.POST("/telemetry/batch", updateHandler.TelemetryBatch)
+	}
+
+	// Update feeds for third-party updater frameworks (API key or feed token authentication)
+	feeds := router.Group("/feeds/:app_id")
+	feeds.Use(middleware.RateLimitByType(rateLimiters, "client"))
+	feeds.Use(middleware.FeedAuth())
+	{
+		feeds.GET("/:channel/appcast.xml", feedHandler.Appcast)
+		feeds.GET("/release-notes/:version", feedHandler.ReleaseNotes)
+		feeds.GET("/electron/:file", feedHandler.ElectronManifest)
+		feeds.GET("/squirrel/:channel/RELEASES", feedHandler.SquirrelReleases)
+	}
+
+	// Serve static files for admin frontend (public access)
+	router.Static("/admin-ui", "./web/admin")
+
+	// Serve uploaded files (public access for download)
+	router.Static("/uploads", "./uploads")
+
+	// SPA fallback for admin frontend - handle all sub-routes
+	router.NoRoute(func(c *gin.Context) {
+		// Check if the request is for admin-ui
+		if strings.HasPrefix(c.Request.URL.Path, "/admin-ui/") {
+			// Serve index.html for SPA routing
+			c.File("./web/admin/index.html")
+			return
+		}
+		// Default 404 for other routes
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Route not found",
+			"path":  c.Request.URL.Path,
+		})
+	})
+
+	// Redirect root to admin frontend
+	router.GET("/", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/admin-ui/")
+	})
+
+	return router
+}
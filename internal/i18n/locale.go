@@ -0,0 +1,120 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NormalizeLocale converts a locale tag into its canonical form, e.g. "zh_cn" becomes "zh-CN"
+func NormalizeLocale(locale string) string {
+	locale = strings.TrimSpace(strings.ReplaceAll(locale, "_", "-"))
+	if locale == "" {
+		return ""
+	}
+
+	parts := strings.Split(locale, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch len(parts[i]) {
+		case 2:
+			// Region subtag (e.g. CN, US)
+			parts[i] = strings.ToUpper(parts[i])
+		case 4:
+			// Script subtag (e.g. Hans, Hant)
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		default:
+			parts[i] = strings.ToLower(parts[i])
+		}
+	}
+
+	return strings.Join(parts, "-")
+}
+
+// baseLanguage returns the primary language subtag of a locale, e.g. "zh" for "zh-CN"
+func baseLanguage(locale string) string {
+	if idx := strings.Index(locale, "-"); idx >= 0 {
+		return locale[:idx]
+	}
+	return locale
+}
+
+// ParseAcceptLanguage parses an Accept-Language header (or a single locale tag)
+// into a list of normalized locales ordered by preference
+func ParseAcceptLanguage(header string) []string {
+	type weightedLocale struct {
+		locale string
+		weight float64
+		index  int
+	}
+
+	var weighted []weightedLocale
+	for i, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := NormalizeLocale(fields[0])
+		if locale == "" || locale == "*" {
+			continue
+		}
+
+		weight := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					weight = q
+				}
+			}
+		}
+		if weight <= 0 {
+			continue
+		}
+
+		weighted = append(weighted, weightedLocale{locale: locale, weight: weight, index: i})
+	}
+
+	// Higher weight first, keep header order for equal weights
+	sort.SliceStable(weighted, func(i, j int) bool {
+		return weighted[i].weight > weighted[j].weight
+	})
+
+	locales := make([]string, 0, len(weighted))
+	for _, w := range weighted {
+		locales = append(locales, w.locale)
+	}
+	return locales
+}
+
+// MatchLocale picks the best available locale for the given preferences.
+// For each preference an exact match wins, then a locale sharing the same base language.
+// Returns false if none of the preferences can be satisfied.
+func MatchLocale(preferences, available []string) (string, bool) {
+	if len(preferences) == 0 || len(available) == 0 {
+		return "", false
+	}
+
+	normalized := make([]string, len(available))
+	for i, locale := range available {
+		normalized[i] = NormalizeLocale(locale)
+	}
+
+	for _, preference := range preferences {
+		preference = NormalizeLocale(preference)
+
+		// Exact match
+		for i, locale := range normalized {
+			if strings.EqualFold(locale, preference) {
+				return available[i], true
+			}
+		}
+
+		// Same base language (e.g. "zh" matches "zh-CN" and vice versa)
+		base := baseLanguage(preference)
+		for i, locale := range normalized {
+			if baseLanguage(locale) == base {
+				return available[i], true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"en", "en"},
+		{"EN-us", "en-US"},
+		{"zh_cn", "zh-CN"},
+		{"zh-hant-tw", "zh-Hant-TW"},
+		{" fr ", "fr"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := NormalizeLocale(tt.input); result != tt.expected {
+				t.Errorf("NormalizeLocale(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected []string
+	}{
+		{"Single tag", "zh-CN", []string{"zh-CN"}},
+		{"Weighted", "en;q=0.5,zh-CN,zh;q=0.9", []string{"zh-CN", "zh", "en"}},
+		{"Equal weights keep order", "fr,de", []string{"fr", "de"}},
+		{"Wildcard and zero weight skipped", "*,ja;q=0", []string{}},
+		{"Empty", "", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseAcceptLanguage(tt.header)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ParseAcceptLanguage(%q) = %v, want %v", tt.header, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	tests := []struct {
+		name        string
+		preferences []string
+		available   []string
+		expected    string
+		found       bool
+	}{
+		{"Exact match", []string{"zh-CN"}, []string{"en", "zh-CN"}, "zh-CN", true},
+		{"Case insensitive", []string{"zh-cn"}, []string{"zh-CN"}, "zh-CN", true},
+		{"Base language fallback", []string{"zh"}, []string{"en", "zh-CN"}, "zh-CN", true},
+		{"Regional to base", []string{"en-GB"}, []string{"en", "zh-CN"}, "en", true},
+		{"Preference order wins", []string{"ja", "en"}, []string{"en", "zh-CN"}, "en", true},
+		{"No match", []string{"fr"}, []string{"en", "zh-CN"}, "", false},
+		{"No preferences", nil, []string{"en"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, found := MatchLocale(tt.preferences, tt.available)
+			if result != tt.expected || found != tt.found {
+				t.Errorf("MatchLocale(%v, %v) = (%q, %v), want (%q, %v)",
+					tt.preferences, tt.available, result, found, tt.expected, tt.found)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Package metrics defines the Prometheus metrics of the VerTree server.
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "vertree"
+
+// Outcome of an update check that offered an update, other outcomes are the reasons why no
+// update is offered, or CheckOutcomeError
+const (
+	CheckOutcomeUpdate = "update"
+	CheckOutcomeError  = "error"
+)
+
+var (
+	// HTTPRequests counts the HTTP requests per route and status
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes the latency of the HTTP requests per route
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// UpdateChecks counts the update checks of the REST and gRPC client APIs per outcome
+	UpdateChecks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "update_checks_total",
+		Help:      "Update checks by application and outcome: update, a no-update reason such as up_to_date or excluded_by_rollout, or error.",
+	}, []string{"app_id", "outcome"})
+
+	// RateLimitRejections counts the requests rejected by the rate limiters
+	RateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected by rate limiting by limiter.",
+	}, []string{"limiter"})
+
+	// InstallResults counts the installation results reported by clients
+	InstallResults = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "install_results_total",
+		Help:      "Installation results reported by clients by application and result (success or failed).",
+	}, []string{"app_id", "result"})
+)
+
+// RegisterDBStats exposes the connection pool stats of the database
+func RegisterDBStats(db *sql.DB, name string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, name))
+}
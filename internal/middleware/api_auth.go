@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
 	"net/http"
 	"strings"
 
@@ -15,16 +18,14 @@ func APIKeyAuth() gin.HandlerFunc {
 		// Extract authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, models.UnauthorizedResponse("Missing Authorization header"))
-			c.Abort()
+			AbortWithError(c, models.UnauthorizedResponse("Missing Authorization header"), models.ErrCodeMissingCredentials)
 			return
 		}
 
 		// Check if it starts with "Bearer "
 		const bearerPrefix = "Bearer "
 		if !strings.HasPrefix(authHeader, bearerPrefix) {
-			c.JSON(http.StatusUnauthorized, models.UnauthorizedResponse("Invalid authorization format. Use 'Bearer <app_id>:<api_key>'"))
-			c.Abort()
+			AbortWithError(c, models.UnauthorizedResponse("Invalid authorization format. Use 'Bearer <app_id>:<api_key>'"), models.ErrCodeInvalidCredentials)
 			return
 		}
 
@@ -34,8 +35,7 @@ func APIKeyAuth() gin.HandlerFunc {
 		// Parse app_id and api_key
 		parts := strings.SplitN(token, ":", 2)
 		if len(parts) != 2 {
-			c.JSON(http.StatusUnauthorized, models.UnauthorizedResponse("Invalid token format. Use '<app_id>:<api_key>'"))
-			c.Abort()
+			AbortWithError(c, models.UnauthorizedResponse("Invalid token format. Use '<app_id>:<api_key>'"), models.ErrCodeInvalidCredentials)
 			return
 		}
 
@@ -43,8 +43,7 @@ func APIKeyAuth() gin.HandlerFunc {
 		apiKey := parts[1]
 
 		if appID == "" || apiKey == "" {
-			c.JSON(http.StatusUnauthorized, models.UnauthorizedResponse("App ID and API key cannot be empty"))
-			c.Abort()
+			AbortWithError(c, models.UnauthorizedResponse("App ID and API key cannot be empty"), models.ErrCodeInvalidCredentials)
 			return
 		}
 
@@ -52,8 +51,7 @@ func APIKeyAuth() gin.HandlerFunc {
 		appService := services.NewApplicationService()
 		app, key, err := appService.ValidateAPIKey(appID, apiKey)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, models.UnauthorizedResponse("Invalid credentials"))
-			c.Abort()
+			AbortWithError(c, models.UnauthorizedResponse("Invalid credentials"), models.ErrCodeInvalidCredentials)
 			return
 		}
 
@@ -72,8 +70,7 @@ func RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		permissions, exists := c.Get("api_key_permissions")
 		if !exists {
-			c.JSON(http.StatusForbidden, models.ErrorResponseWithCode(403, "Permissions not found in context", nil))
-			c.Abort()
+			AbortWithError(c, models.ErrorResponseWithCode(403, "Permissions not found in context", nil), models.ErrCodePermissionDenied)
 			return
 		}
 
@@ -84,8 +81,7 @@ func RequirePermission(permission string) gin.HandlerFunc {
 		case models.PermissionsList:
 			permissionList = []string(p)
 		default:
-			c.JSON(http.StatusForbidden, models.ErrorResponseWithCode(403, "Invalid permissions format", nil))
-			c.Abort()
+			AbortWithError(c, models.ErrorResponseWithCode(403, "Invalid permissions format", nil), models.ErrCodePermissionDenied)
 			return
 		}
 
@@ -99,8 +95,7 @@ func RequirePermission(permission string) gin.HandlerFunc {
 		}
 
 		if !hasPermission {
-			c.JSON(http.StatusForbidden, models.ErrorResponseWithCode(403, "Insufficient permissions", nil))
-			c.Abort()
+			AbortWithError(c, models.ErrorResponseWithCode(403, "Insufficient permissions", nil), models.ErrCodePermissionDenied)
 			return
 		}
 
@@ -166,3 +161,138 @@ func OptionalAPIKeyAuth() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// PublicAppAccess creates a middleware, used after OptionalAPIKeyAuth, that lets requests
+// without an API key through for public applications. The application is taken from the
+// "app_id" query parameter or JSON body field. Anonymous requests are rate limited per IP
+// with limiter and only get the check_update permission.
+func PublicAppAccess(limiter *RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, authenticated := c.Get("api_key"); authenticated {
+			c.Next()
+			return
+		}
+
+		// Don't silently downgrade requests with invalid credentials to anonymous ones
+		if c.GetHeader("Authorization") != "" {
+			AbortWithError(c, models.UnauthorizedResponse("Invalid credentials"), models.ErrCodeInvalidCredentials)
+			return
+		}
+
+		appID := publicAppID(c)
+		if appID == "" {
+			AbortWithError(c, models.UnauthorizedResponse("Missing Authorization header"), models.ErrCodeMissingCredentials)
+			return
+		}
+
+		appService := services.NewApplicationService()
+		app, err := appService.GetPublicApplication(appID)
+		if err != nil {
+			AbortWithError(c, models.UnauthorizedResponse("Missing Authorization header"), models.ErrCodeMissingCredentials)
+			return
+		}
+
+		if limiter != nil && !limiter.Allow(c.ClientIP()) {
+			SetRetryAfter(c, limiter.RetryAfter(c.ClientIP()))
+			c.JSON(http.StatusTooManyRequests, models.ErrorResponseWithCodeAndError(
+				http.StatusTooManyRequests,
+				"Rate limit exceeded",
+				"RATE_LIMIT_EXCEEDED",
+			))
+			c.Abort()
+			return
+		}
+
+		c.Set("app_id", app.AppID)
+		c.Set("app", app)
+		c.Set("anonymous", true)
+		c.Set("api_key_permissions", models.PermissionsList{"check_update"})
+
+		c.Next()
+	}
+}
+
+// publicAppID returns the app ID of an anonymous request from the query or the JSON body,
+// leaving the body readable for the handler
+func publicAppID(c *gin.Context) string {
+	if appID := c.Query("app_id"); appID != "" {
+		return appID
+	}
+	if c.Request.Body == nil || c.ContentType() != gin.MIMEJSON {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		return ""
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var payload struct {
+		AppID string `json:"app_id"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return payload.AppID
+}
+
+// FeedAuth creates a middleware for update feeds of third-party updater frameworks.
+// The application in the :app_id path parameter is authenticated either by an API key
+// with the check_update permission, or by its feed token passed as the "token" query
+// parameter or the X-Feed-Token header, since such clients often can't set an
+// Authorization header.
+func FeedAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appID := c.Param("app_id")
+		appService := services.NewApplicationService()
+
+		token := c.Query("token")
+		if token == "" {
+			token = c.GetHeader("X-Feed-Token")
+		}
+		if token != "" {
+			app, err := appService.ValidateFeedToken(appID, token)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, models.UnauthorizedResponse("Invalid credentials"))
+				c.Abort()
+				return
+			}
+
+			c.Set("app_id", app.AppID)
+			c.Set("app", app)
+			c.Set("feed_token", token)
+			c.Next()
+			return
+		}
+
+		const bearerPrefix = "Bearer "
+		authHeader := c.GetHeader("Authorization")
+		if !strings.HasPrefix(authHeader, bearerPrefix) {
+			c.JSON(http.StatusUnauthorized, models.UnauthorizedResponse("Missing API key or feed token"))
+			c.Abort()
+			return
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(authHeader, bearerPrefix), ":", 2)
+		if len(parts) != 2 || parts[0] != appID || parts[1] == "" {
+			c.JSON(http.StatusUnauthorized, models.UnauthorizedResponse("Invalid credentials"))
+			c.Abort()
+			return
+		}
+
+		app, key, err := appService.ValidateAPIKey(parts[0], parts[1])
+		if err != nil || !key.Permissions.Has("check_update") {
+			c.JSON(http.StatusUnauthorized, models.UnauthorizedResponse("Invalid credentials"))
+			c.Abort()
+			return
+		}
+
+		c.Set("app_id", app.AppID)
+		c.Set("app", app)
+		c.Set("api_key", key)
+		c.Set("api_key_permissions", key.Permissions)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// typedErrorsKey is the context key marking requests of an API version with typed errors
+const typedErrorsKey = "typed_errors"
+
+// TypedErrors creates a middleware for API versions (e.g. the client API v2) whose error
+// responses carry a stable machine-readable code in the "error" field
+func TypedErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(typedErrorsKey, true)
+		c.Next()
+	}
+}
+
+// RespondError writes an error response. For requests with typed errors, errorCode replaces
+// the free-text error detail, which is appended to the message instead, so that the
+// responses of older API versions stay unchanged.
+func RespondError(c *gin.Context, response *models.ErrorResponse, errorCode string) {
+	if c.GetBool(typedErrorsKey) {
+		typed := *response
+		if typed.Error != "" {
+			typed.Message += ": " + typed.Error
+		}
+		typed.Error = errorCode
+		response = &typed
+	}
+	c.JSON(response.Code, response)
+}
+
+// AbortWithError writes an error response like RespondError and aborts the request
+func AbortWithError(c *gin.Context, response *models.ErrorResponse, errorCode string) {
+	RespondError(c, response, errorCode)
+	c.Abort()
+}
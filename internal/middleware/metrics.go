@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/metrics"
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// streamingRoutes are long-lived streams, their duration is the time the client stayed
+// connected and would distort the latency histogram
+var streamingRoutes = map[string]bool{
+	"/api/v1/events": true,
+}
+
+// Metrics records the count and latency of requests per route. Requests that match no
+// route are recorded under the route "unmatched" to bound the number of series. Streaming
+// routes are only counted.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		if !streamingRoutes[route] {
+			metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+		}
+	}
+}
+
+// MetricsAuth protects the metrics endpoint with "Authorization: Bearer <token>". An empty
+// token leaves the endpoint open, for scrapers on a private network; the router doesn't
+// serve the endpoint without a token in production.
+func MetricsAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		expected := "Bearer " + token
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte(expected)) != 1 {
+			c.JSON(http.StatusUnauthorized, models.UnauthorizedResponse("Invalid metrics token"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -2,15 +2,18 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/Run-Panel/VerTree/internal/metrics"
 	"github.com/Run-Panel/VerTree/internal/models"
 	"github.com/gin-gonic/gin"
 )
 
 // RateLimiter represents a rate limiter
 type RateLimiter struct {
+	name    string // Label of the rejections metric
 	clients map[string]*ClientLimiter
 	mutex   sync.RWMutex
 	limit   int
@@ -72,9 +75,38 @@ func (rl *RateLimiter) Allow(clientID string) bool {
 		return true
 	}
 
+	metrics.RateLimitRejections.WithLabelValues(rl.name).Inc()
 	return false
 }
 
+// RetryAfter returns how long a client has to wait until its requests are allowed again
+func (rl *RateLimiter) RetryAfter(clientID string) time.Duration {
+	rl.mutex.RLock()
+	client, exists := rl.clients[clientID]
+	rl.mutex.RUnlock()
+
+	if !exists {
+		return 0
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if wait := rl.window - time.Since(client.lastReset); wait > 0 && client.tokens == 0 {
+		return wait
+	}
+	return 0
+}
+
+// SetRetryAfter sets the Retry-After header of a rate limited response in whole seconds
+func SetRetryAfter(c *gin.Context, wait time.Duration) {
+	seconds := int((wait + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(seconds))
+}
+
 // cleanup removes old clients periodically
 func (rl *RateLimiter) cleanup() {
 	ticker := time.NewTicker(rl.window)
@@ -108,6 +140,7 @@ func RateLimitMiddleware(limiter *RateLimiter) gin.HandlerFunc {
 		}
 
 		if !limiter.Allow(clientIP) {
+			SetRetryAfter(c, limiter.RetryAfter(clientIP))
 			c.JSON(http.StatusTooManyRequests, models.ErrorResponseWithCodeAndError(
 				http.StatusTooManyRequests,
 				"Rate limit exceeded",
@@ -123,12 +156,17 @@ func RateLimitMiddleware(limiter *RateLimiter) gin.HandlerFunc {
 
 // CreateRateLimiters creates different rate limiters for different endpoints
 func CreateRateLimiters() map[string]*RateLimiter {
-	return map[string]*RateLimiter{
+	limiters := map[string]*RateLimiter{
 		"auth":   NewRateLimiter(5, time.Minute),     // 5 requests per minute for auth endpoints
 		"admin":  NewRateLimiter(100, time.Minute),   // 100 requests per minute for admin endpoints
 		"client": NewRateLimiter(1000, time.Minute),  // 1000 requests per minute for client endpoints
+		"public": NewRateLimiter(60, time.Minute),    // 60 requests per minute for anonymous requests to public apps
 		"global": NewRateLimiter(10000, time.Minute), // 10000 requests per minute globally
 	}
+	for name, limiter := range limiters {
+		limiter.name = name
+	}
+	return limiters
 }
 
 // RateLimitByType creates a rate limiting middleware for specific endpoint types
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // APIResponse represents a generic API response
 type APIResponse struct {
 	Code    int         `json:"code"`
@@ -40,6 +42,16 @@ type CheckUpdateRequest struct {
 	Region         string `json:"region"`
 	Arch           string `json:"arch"`
 	OS             string `json:"os"`
+	Locale         string `json:"locale"` // Preferred locale(s), falls back to the Accept-Language header
+
+	IncludeChangelog bool `json:"include_changelog"` // Include every version between current and latest
+
+	ReleaseLine string `json:"release_line"` // Pin the client to a release line, e.g. "1.x-lts"
+
+	// Compatibility information used to skip versions the client cannot run
+	OSVersion    string            `json:"os_version"`
+	Dependencies map[string]string `json:"dependencies"` // Installed companion app name -> version
+	Capabilities []string          `json:"capabilities"`
 }
 
 // CheckUpdateResponse represents the client update check response
@@ -54,17 +66,56 @@ type CheckUpdateResponse struct {
 	Description       string `json:"description,omitempty"`
 	ReleaseNotes      string `json:"release_notes,omitempty"`
 	MinUpgradeVersion string `json:"min_upgrade_version,omitempty"`
+	Locale            string `json:"locale,omitempty"`
+	ReleaseLine       string `json:"release_line,omitempty"` // Release line the offered version was selected from
+
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	SupportStatus *SupportStatus `json:"support_status,omitempty"` // Support status of the client's current version
+
+	NextCheckAfter int `json:"next_check_after,omitempty"` // Seconds the client should wait before checking again
 }
 
-// StatsRequest represents the statistics query request
+// SupportStatus represents the support status of a version
+type SupportStatus struct {
+	Status       string     `json:"status"` // supported, deprecated, eol or unknown
+	DeprecatedAt *time.Time `json:"deprecated_at,omitempty"`
+	EOLAt        *time.Time `json:"eol_at,omitempty"`
+}
+
+// ChangelogEntry represents the release information of one version in an aggregated changelog
+type ChangelogEntry struct {
+	Version         string     `json:"version"`
+	Title           string     `json:"title"`
+	ReleaseNotes    string     `json:"release_notes,omitempty"`
+	BreakingChanges string     `json:"breaking_changes,omitempty"`
+	IsForced        bool       `json:"is_forced,omitempty"`
+	PublishTime     *time.Time `json:"publish_time,omitempty"`
+	Locale          string     `json:"locale,omitempty"`
+}
+
+// StatsRequest represents the statistics query request. From and To, both inclusive UTC
+// days, select an arbitrary date range instead of a period ending today.
 type StatsRequest struct {
 	Period string `json:"period" form:"period" validate:"oneof=1d 7d 30d 90d"`
+	From   string `json:"from" form:"from"`
+	To     string `json:"to" form:"to"`
 	Action string `json:"action" form:"action" validate:"oneof=all check download install success failed"`
+	StatsFilter
+}
+
+// StatsFilter restricts statistics to an application and channel, both optional
+type StatsFilter struct {
+	AppID   string `json:"app_id" form:"app_id"`
+	Channel string `json:"channel" form:"channel"`
 }
 
-// StatsResponse represents the statistics response
+// StatsResponse represents the statistics response. Client counts of a date range are the
+// counts of its busiest day, as clients can't be told apart across the daily rollups.
 type StatsResponse struct {
-	TotalUsers          int64            `json:"total_users"`
+	From                string           `json:"from"`
+	To                  string           `json:"to"`
+	PeakDailyUsers      int64            `json:"peak_daily_users"` // Distinct clients of the busiest day
 	TotalDownloads      int64            `json:"total_downloads"`
 	SuccessRate         float64          `json:"success_rate"`
 	VersionDistribution map[string]int64 `json:"version_distribution"`
@@ -72,6 +123,73 @@ type StatsResponse struct {
 	DailyStats          []DailyStat      `json:"daily_stats"`
 }
 
+// SupportReportEntry represents the active clients on one deprecated or end-of-life version
+type SupportReportEntry struct {
+	AppID         string     `json:"app_id"`
+	Version       string     `json:"version"`
+	Status        string     `json:"status"`
+	DeprecatedAt  *time.Time `json:"deprecated_at,omitempty"`
+	EOLAt         *time.Time `json:"eol_at,omitempty"`
+	ActiveClients int64      `json:"active_clients"`
+}
+
+// SupportReportResponse represents the report of active clients on unsupported versions
+type SupportReportResponse struct {
+	Days                      int                  `json:"days"`
+	ActiveClientsOnEOL        int64                `json:"active_clients_on_eol"`
+	ActiveClientsOnDeprecated int64                `json:"active_clients_on_deprecated"`
+	Versions                  []SupportReportEntry `json:"versions"`
+}
+
+// VersionFunnel represents how clients progressed through the update to a version. Every
+// stage counts the clients that reached it, whether or not they were offered the version,
+// and a client that failed and later succeeded counts as succeeded. The rates follow the
+// clients of a stage to the next: offer based rates only count clients that were offered
+// the version. Rates are in percent, medians in seconds.
+type VersionFunnel struct {
+	Version                 string     `json:"version"`
+	Channel                 string     `json:"channel"`
+	PublishTime             *time.Time `json:"publish_time"`
+	Offered                 int64      `json:"offered"`
+	Downloaded              int64      `json:"downloaded"`
+	Succeeded               int64      `json:"succeeded"`
+	Failed                  int64      `json:"failed"`
+	DownloadRate            float64    `json:"download_rate"`                      // Offered clients that downloaded
+	InstallRate             float64    `json:"install_rate"`                       // Downloading clients that succeeded
+	ConversionRate          float64    `json:"conversion_rate"`                    // Offered clients that succeeded
+	MedianOfferToDownload   int64      `json:"median_offer_to_download_seconds"`   // From the first offer to the first download
+	MedianDownloadToInstall int64      `json:"median_download_to_install_seconds"` // From the first download to the first result
+}
+
+// FunnelResponse represents the update funnels of the released versions of an application
+type FunnelResponse struct {
+	AppID    string          `json:"app_id"`
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Versions []VersionFunnel `json:"versions"`
+}
+
+// InstallErrorGroup represents the install failures of an application version that share
+// an error signature
+type InstallErrorGroup struct {
+	AppID     string    `json:"app_id"`
+	Version   string    `json:"version"`
+	Signature string    `json:"signature"`
+	Count     int64     `json:"count"`
+	Clients   int64     `json:"clients"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Samples   []string  `json:"samples"` // Distinct error messages, the latest first
+}
+
+// InstallErrorsResponse represents the most frequent install errors of a date range
+type InstallErrorsResponse struct {
+	From          string              `json:"from"`
+	To            string              `json:"to"`
+	TotalFailures int64               `json:"total_failures"`
+	Errors        []InstallErrorGroup `json:"errors"`
+}
+
 // DailyStat represents daily statistics
 type DailyStat struct {
 	Date      string `json:"date"`
@@ -0,0 +1,65 @@
+package models
+
+// Error codes of the client API v2, returned in the "error" field of error responses
+const (
+	ErrCodeInvalidRequest      = "INVALID_REQUEST"
+	ErrCodeMissingCredentials  = "MISSING_CREDENTIALS"
+	ErrCodeInvalidCredentials  = "INVALID_CREDENTIALS"
+	ErrCodePermissionDenied    = "PERMISSION_DENIED"
+	ErrCodeRateLimitExceeded   = "RATE_LIMIT_EXCEEDED"
+	ErrCodeAppNotFound         = "APP_NOT_FOUND"
+	ErrCodeAppInactive         = "APP_INACTIVE"
+	ErrCodeAppMismatch         = "APP_MISMATCH"
+	ErrCodeChannelNotFound     = "CHANNEL_NOT_FOUND"
+	ErrCodeChannelDisabled     = "CHANNEL_DISABLED"
+	ErrCodeChannelInactive     = "CHANNEL_INACTIVE"
+	ErrCodeReleaseLineNotFound = "RELEASE_LINE_NOT_FOUND"
+	ErrCodeReleaseLineInactive = "RELEASE_LINE_INACTIVE"
+	ErrCodeInternal            = "INTERNAL_ERROR"
+)
+
+// Reasons why an update check offers no update
+const (
+	NoUpdateReasonUpToDate        = "up_to_date"
+	NoUpdateReasonNoRelease       = "no_release"            // No published version in the channel (or release line)
+	NoUpdateReasonIncompatible    = "incompatible"          // OS version, dependencies or capabilities don't match
+	NoUpdateReasonBelowMinimum    = "below_minimum_version" // Current version is below the min_upgrade_version
+	NoUpdateReasonExcludedRollout = "excluded_by_rollout"
+	NoUpdateReasonNoPlatformBuild = "no_build_for_platform"
+)
+
+// ClientError is an error of an update check that clients can react to, with the HTTP
+// status and the stable error code of the client API v2
+type ClientError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+// Error returns the message of the error, as returned by the client API v1
+func (e *ClientError) Error() string {
+	return e.Message
+}
+
+// UpdateDecision is the outcome of an update check
+type UpdateDecision struct {
+	Response *CheckUpdateResponse
+	Reason   string // Why no update is offered, empty when there is an update
+	Detail   string // Human readable explanation of the reason
+}
+
+// CheckUpdateResponseV2 represents the client update check response of the client API v2
+type CheckUpdateResponseV2 struct {
+	*CheckUpdateResponse
+	Reason       string `json:"reason,omitempty"`
+	ReasonDetail string `json:"reason_detail,omitempty"`
+}
+
+// ToResponseV2 converts the decision to the client API v2 response
+func (d *UpdateDecision) ToResponseV2() *CheckUpdateResponseV2 {
+	return &CheckUpdateResponseV2{
+		CheckUpdateResponse: d.Response,
+		Reason:              d.Reason,
+		ReasonDetail:        d.Detail,
+	}
+}
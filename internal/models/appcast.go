@@ -0,0 +1,78 @@
+package models
+
+import "encoding/xml"
+
+// XML namespaces used by Sparkle appcasts
+const (
+	SparkleNamespace      = "http://www.andymatuschak.org/xml-namespaces/sparkle"
+	DublinCoreNamespace   = "http://purl.org/dc/elements/1.1/"
+	AppcastEnclosureType  = "application/octet-stream"
+	AppcastContentType    = "application/rss+xml; charset=utf-8"
+	ReleaseNotesMediaType = "text/html; charset=utf-8"
+)
+
+// Appcast represents a Sparkle appcast RSS feed
+type Appcast struct {
+	XMLName      xml.Name       `xml:"rss"`
+	Version      string         `xml:"version,attr"`
+	XMLNSSparkle string         `xml:"xmlns:sparkle,attr"`
+	XMLNSDC      string         `xml:"xmlns:dc,attr"`
+	Channel      AppcastChannel `xml:"channel"`
+}
+
+// AppcastChannel represents the channel element of an appcast
+type AppcastChannel struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description"`
+	Items       []AppcastItem `xml:"item"`
+}
+
+// AppcastItem represents a single version in an appcast
+type AppcastItem struct {
+	Title                string                    `xml:"title"`
+	PubDate              string                    `xml:"pubDate,omitempty"`
+	Version              string                    `xml:"sparkle:version"`
+	ShortVersionString   string                    `xml:"sparkle:shortVersionString"`
+	MinimumSystemVersion string                    `xml:"sparkle:minimumSystemVersion,omitempty"`
+	MaximumSystemVersion string                    `xml:"sparkle:maximumSystemVersion,omitempty"`
+	CriticalUpdate       *struct{}                 `xml:"sparkle:criticalUpdate,omitempty"`
+	ReleaseNotesLinks    []AppcastReleaseNotesLink `xml:"sparkle:releaseNotesLink"`
+	Enclosure            AppcastEnclosure          `xml:"enclosure"`
+}
+
+// AppcastReleaseNotesLink represents a (localized) release notes link of an appcast item
+type AppcastReleaseNotesLink struct {
+	Lang string `xml:"xml:lang,attr,omitempty"`
+	URL  string `xml:",chardata"`
+}
+
+// AppcastEnclosure represents the downloadable update of an appcast item
+type AppcastEnclosure struct {
+	URL         string `xml:"url,attr"`
+	Length      int64  `xml:"length,attr"`
+	Type        string `xml:"type,attr"`
+	EdSignature string `xml:"sparkle:edSignature,attr,omitempty"`
+}
+
+// ElectronUpdateInfo represents an electron-updater manifest such as latest.yml or latest-mac.yml
+type ElectronUpdateInfo struct {
+	Version           string             `yaml:"version"`
+	Files             []ElectronFileInfo `yaml:"files"`
+	Path              string             `yaml:"path"`
+	SHA512            string             `yaml:"sha512"`
+	ReleaseDate       string             `yaml:"releaseDate,omitempty"`
+	ReleaseName       string             `yaml:"releaseName,omitempty"`
+	ReleaseNotes      string             `yaml:"releaseNotes,omitempty"`
+	StagingPercentage int                `yaml:"stagingPercentage,omitempty"`
+}
+
+// ElectronFileInfo represents a downloadable file of an electron-updater manifest
+type ElectronFileInfo struct {
+	URL    string `yaml:"url"`
+	SHA512 string `yaml:"sha512"`
+	Size   int64  `yaml:"size"`
+}
+
+// ElectronManifestContentType is the content type of electron-updater manifests
+const ElectronManifestContentType = "text/yaml; charset=utf-8"
@@ -24,6 +24,14 @@ type Application struct {
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// FeedTokenHash is the SHA256 hash of the token authenticating update feeds
+	// (e.g. Sparkle appcasts) whose clients can't send an API key
+	FeedTokenHash string `gorm:"column:feed_token_hash;size:64" json:"-"`
+
+	// IsPublic allows anonymous update checks without an API key, e.g. for open-source
+	// apps that can't keep a secret in their binaries
+	IsPublic bool `gorm:"default:false" json:"is_public"`
+
 	// Associations
 	CreatedByAdmin Admin            `gorm:"foreignKey:CreatedBy" json:"-"`
 	Versions       []Version        `gorm:"foreignKey:AppID;references:AppID" json:"-"`
@@ -37,6 +45,7 @@ type ApplicationRequest struct {
 	Description string `json:"description"`
 	Icon        string `json:"icon_url"`
 	IsActive    bool   `json:"is_active"`
+	IsPublic    bool   `json:"is_public"`
 }
 
 // ApplicationResponse represents the response format for an application
@@ -51,6 +60,15 @@ type ApplicationResponse struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	KeysCount   int       `json:"keys_count"`
+
+	HasFeedToken bool `json:"has_feed_token"`
+	IsPublic     bool `json:"is_public"`
+}
+
+// FeedTokenResponse represents the response when a feed token is rotated (only shown once)
+type FeedTokenResponse struct {
+	AppID     string `json:"app_id"`
+	FeedToken string `json:"feed_token"`
 }
 
 // ApplicationKey represents an API key for an application
@@ -136,6 +154,16 @@ func (p *PermissionsList) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, p)
 }
 
+// Has checks if the list grants a permission, either directly or through "*"
+func (p PermissionsList) Has(permission string) bool {
+	for _, item := range p {
+		if item == permission || item == "*" {
+			return true
+		}
+	}
+	return false
+}
+
 // BeforeCreate sets the app_id before creating an application
 func (a *Application) BeforeCreate(tx *gorm.DB) error {
 	if a.AppID == "" {
@@ -172,6 +200,9 @@ func (a *Application) ToResponse() *ApplicationResponse {
 		CreatedBy:   a.CreatedBy,
 		CreatedAt:   a.CreatedAt,
 		UpdatedAt:   a.UpdatedAt,
+
+		HasFeedToken: a.FeedTokenHash != "",
+		IsPublic:     a.IsPublic,
 	}
 }
 
@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// BundleFormatVersion is the version of the application bundle format
+const BundleFormatVersion = 1
+
+// BundleManifestName is the name of the manifest file inside a bundle archive,
+// and BundleFilesDir the directory holding the uploaded files included in it
+const (
+	BundleManifestName = "bundle.json"
+	BundleFilesDir     = "files/"
+)
+
+// Conflict modes for importing a bundle into an instance that already has the application
+const (
+	BundleConflictFail      = "fail"      // Abort the import
+	BundleConflictMerge     = "merge"     // Keep existing data, only add what is missing
+	BundleConflictOverwrite = "overwrite" // Replace existing settings and versions with the bundle's, keep versions it doesn't contain
+)
+
+// ApplicationBundle represents an exported application that can be imported on another instance
+type ApplicationBundle struct {
+	FormatVersion int                  `json:"format_version"`
+	ExportedAt    time.Time            `json:"exported_at"`
+	Application   BundleApplication    `json:"application"`
+	Channels      []BundleChannel      `json:"channels"`
+	ReleaseLines  []ReleaseLineRequest `json:"release_lines"`
+	Versions      []BundleVersion      `json:"versions"`
+	Files         []string             `json:"files"` // Uploaded files included in the archive
+}
+
+// BundleApplication represents the metadata of a bundled application
+type BundleApplication struct {
+	AppID       string `json:"app_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon_url"`
+	IsActive    bool   `json:"is_active"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+// BundleChannel represents the channel settings and rollout configuration of a bundled application
+type BundleChannel struct {
+	ChannelRequest
+	IsEnabled         bool `json:"is_enabled"`
+	AutoPublish       bool `json:"auto_publish"`
+	RolloutPercentage int  `json:"rollout_percentage"`
+	CheckInterval     int  `json:"check_interval,omitempty"`
+}
+
+// BundleVersion represents a bundled version with its localizations and support window
+type BundleVersion struct {
+	ImportVersion
+	DeprecatedAt  *time.Time           `json:"deprecated_at"`
+	EOLAt         *time.Time           `json:"eol_at"`
+	Localizations []BundleLocalization `json:"localizations"`
+}
+
+// BundleLocalization represents a bundled localization of a version
+type BundleLocalization struct {
+	Locale string `json:"locale"`
+	VersionLocalizationRequest
+}
+
+// BundleImportReport represents the result of importing an application bundle
+type BundleImportReport struct {
+	AppID           string `json:"app_id"`
+	Name            string `json:"name"`
+	Action          string `json:"action"` // created, merged or overwritten
+	ChannelsApplied int    `json:"channels_applied"`
+	LinesApplied    int    `json:"release_lines_applied"`
+	VersionsCreated int    `json:"versions_created"`
+	VersionsUpdated int    `json:"versions_updated"`
+	VersionsSkipped int    `json:"versions_skipped"`
+	FilesRestored   int    `json:"files_restored"`
+}
@@ -56,6 +56,7 @@ type ApplicationChannel struct {
 	IsEnabled         bool           `json:"is_enabled" gorm:"default:true"`
 	AutoPublish       bool           `json:"auto_publish" gorm:"default:false"`
 	RolloutPercentage int            `json:"rollout_percentage" gorm:"default:100" validate:"min=0,max=100"`
+	CheckInterval     int            `json:"check_interval" gorm:"default:0" validate:"min=0"` // Seconds between update checks hinted to clients, 0 uses the server default
 	CreatedAt         time.Time      `json:"created_at"`
 	UpdatedAt         time.Time      `json:"updated_at"`
 	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
@@ -77,6 +78,7 @@ type ApplicationChannelRequest struct {
 	IsEnabled         bool   `json:"is_enabled"`
 	AutoPublish       bool   `json:"auto_publish"`
 	RolloutPercentage int    `json:"rollout_percentage" validate:"min=0,max=100"`
+	CheckInterval     int    `json:"check_interval" validate:"min=0"`
 }
 
 // ApplicationChannelResponse represents the response payload for app-channel relationships
@@ -88,6 +90,7 @@ type ApplicationChannelResponse struct {
 	IsEnabled          bool      `json:"is_enabled"`
 	AutoPublish        bool      `json:"auto_publish"`
 	RolloutPercentage  int       `json:"rollout_percentage"`
+	CheckInterval      int       `json:"check_interval"`
 	CreatedAt          time.Time `json:"created_at"`
 	UpdatedAt          time.Time `json:"updated_at"`
 }
@@ -101,6 +104,7 @@ func (ac *ApplicationChannel) ToResponse() *ApplicationChannelResponse {
 		IsEnabled:         ac.IsEnabled,
 		AutoPublish:       ac.AutoPublish,
 		RolloutPercentage: ac.RolloutPercentage,
+		CheckInterval:     ac.CheckInterval,
 		CreatedAt:         ac.CreatedAt,
 		UpdatedAt:         ac.UpdatedAt,
 	}
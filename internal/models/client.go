@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+// Client represents the current state of a client installation of an application,
+// maintained from its update checks and install results
+type Client struct {
+	ID                 uint       `json:"id" gorm:"primaryKey"`
+	AppID              string     `json:"app_id" gorm:"not null;size:50;uniqueIndex:idx_client_app_client"`
+	ClientID           string     `json:"client_id" gorm:"not null;size:128;uniqueIndex:idx_client_app_client"`
+	CurrentVersion     string     `json:"current_version" gorm:"size:50;index"`
+	Channel            string     `json:"channel" gorm:"size:20"`
+	OS                 string     `json:"os" gorm:"size:20"`
+	Arch               string     `json:"arch" gorm:"size:20"`
+	Region             string     `json:"region" gorm:"size:10"`
+	IPAddress          string     `json:"ip_address" gorm:"size:255"`
+	LastInstallVersion string     `json:"last_install_version" gorm:"size:50"`
+	LastInstallStatus  string     `json:"last_install_status" gorm:"size:20"` // success or failed
+	LastInstallError   string     `json:"last_install_error" gorm:"type:text"`
+	LastOfferedVersion string     `json:"last_offered_version" gorm:"size:50"` // Latest update offered to the client
+	FirstSeenAt        time.Time  `json:"first_seen_at"`
+	LastSeenAt         time.Time  `json:"last_seen_at" gorm:"index"`
+	LastCheckAt        *time.Time `json:"last_check_at"`
+	LastInstallAt      *time.Time `json:"last_install_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// TableName returns the table name for Client model
+func (Client) TableName() string {
+	return "clients"
+}
+
+// ClientListRequest represents the filters for listing the clients of an application
+type ClientListRequest struct {
+	Search  string `form:"search"` // Matches part of the client ID
+	Version string `form:"version"`
+	Channel string `form:"channel"`
+	OS      string `form:"os"`
+	Arch    string `form:"arch"`
+	Page    int    `form:"page"`
+	Limit   int    `form:"limit"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// DependencyConstraint represents a required version range of another application
+// installed alongside the client, e.g. {"name": "agent", "version_range": ">=2.1.0"}
+type DependencyConstraint struct {
+	Name         string `json:"name"`
+	VersionRange string `json:"version_range"`
+}
+
+// DependencyList represents a list of dependency constraints stored as JSON
+type DependencyList []DependencyConstraint
+
+// Value implements driver.Valuer interface for database storage
+func (d DependencyList) Value() (driver.Value, error) {
+	if len(d) == 0 {
+		return "[]", nil
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner interface for database retrieval
+func (d *DependencyList) Scan(value interface{}) error {
+	if value == nil {
+		*d = DependencyList{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case string:
+		bytes = []byte(v)
+	case []byte:
+		bytes = v
+	default:
+		return fmt.Errorf("cannot scan %T into DependencyList", value)
+	}
+
+	return json.Unmarshal(bytes, d)
+}
+
+// StringList represents a list of strings stored as JSON
+type StringList []string
+
+// Value implements driver.Valuer interface for database storage
+func (l StringList) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner interface for database retrieval
+func (l *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*l = StringList{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case string:
+		bytes = []byte(v)
+	case []byte:
+		bytes = v
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+
+	return json.Unmarshal(bytes, l)
+}
+
+// Contains checks if the list contains the given value
+func (l StringList) Contains(value string) bool {
+	for _, item := range l {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
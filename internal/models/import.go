@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// Supported import manifest formats
+const (
+	ImportFormatJSON   = "json"
+	ImportFormatYAML   = "yaml"
+	ImportFormatGitHub = "github" // GitHub Releases API JSON export
+)
+
+// ImportManifest represents a release manifest used to bulk import historical versions
+type ImportManifest struct {
+	Channel  string          `json:"channel" yaml:"channel"` // Default channel for versions without one
+	Versions []ImportVersion `json:"versions" yaml:"versions"`
+
+	// Format is the format the manifest was parsed from. GitHub exports may lack checksums,
+	// as GitHub only records digests of assets uploaded since it introduced them.
+	Format string `json:"-" yaml:"-"`
+}
+
+// ImportVersion represents a single version entry of an import manifest
+type ImportVersion struct {
+	Version           string     `json:"version" yaml:"version"`
+	Channel           string     `json:"channel" yaml:"channel"`
+	Title             string     `json:"title" yaml:"title"`
+	Description       string     `json:"description" yaml:"description"`
+	ReleaseNotes      string     `json:"release_notes" yaml:"release_notes"`
+	BreakingChanges   string     `json:"breaking_changes" yaml:"breaking_changes"`
+	MinUpgradeVersion string     `json:"min_upgrade_version" yaml:"min_upgrade_version"`
+	FileURL           string     `json:"file_url" yaml:"file_url"`
+	FileSize          int64      `json:"file_size" yaml:"file_size"`
+	FileChecksum      string     `json:"file_checksum" yaml:"file_checksum"`
+	IsForced          bool       `json:"is_forced" yaml:"is_forced"`
+	IsPublished       bool       `json:"is_published" yaml:"is_published"`
+	PublishTime       *time.Time `json:"publish_time" yaml:"publish_time"`
+
+	MinOSVersion         string         `json:"min_os_version" yaml:"min_os_version"`
+	MaxOSVersion         string         `json:"max_os_version" yaml:"max_os_version"`
+	RequiredDependencies DependencyList `json:"required_dependencies" yaml:"required_dependencies"`
+	RequiredCapabilities StringList     `json:"required_capabilities" yaml:"required_capabilities"`
+
+	Artifacts []VersionArtifactRequest `json:"artifacts" yaml:"artifacts"`
+}
+
+// ImportOptions controls how an import manifest is applied
+type ImportOptions struct {
+	DryRun       bool // Validate only, don't write anything
+	SkipExisting bool // Skip versions that already exist instead of failing
+}
+
+// Import result actions
+const (
+	ImportActionCreate = "create"
+	ImportActionSkip   = "skip"
+)
+
+// ImportVersionResult represents the outcome of importing a single version
+type ImportVersionResult struct {
+	Version   string `json:"version"`
+	Channel   string `json:"channel"`
+	Action    string `json:"action"`
+	Artifacts int    `json:"artifacts"`
+	VersionID uint   `json:"version_id,omitempty"`
+}
+
+// ImportIssue represents a validation problem or warning found in an import manifest
+type ImportIssue struct {
+	Index   int    `json:"index"`
+	Version string `json:"version"`
+	Message string `json:"message"`
+}
+
+// ImportReport represents the validation and result report of an import
+type ImportReport struct {
+	AppID    string                `json:"app_id"`
+	DryRun   bool                  `json:"dry_run"`
+	Total    int                   `json:"total"`
+	Created  int                   `json:"created"`
+	Skipped  int                   `json:"skipped"`
+	Versions []ImportVersionResult `json:"versions"`
+	Errors   []ImportIssue         `json:"errors"`
+	Warnings []ImportIssue         `json:"warnings"` // Problems that don't prevent the import
+}
+
+// Valid reports whether the manifest passed validation
+func (r *ImportReport) Valid() bool {
+	return len(r.Errors) == 0
+}
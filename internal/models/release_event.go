@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Release event types pushed to subscribed clients
+const (
+	ReleaseEventVersionPublished = "version_published"
+	ReleaseEventForcedUpdate     = "forced_update"
+	// ReleaseEventResync tells a reconnecting client that events may have been missed
+	// (e.g. after a server restart) and it should check for updates
+	ReleaseEventResync = "resync"
+)
+
+// ReleaseEvent represents a release notification pushed to clients of an app and channel.
+// Clients react by calling check-update, which applies rollout and update rules.
+type ReleaseEvent struct {
+	ID        uint64    `json:"id"`
+	Type      string    `json:"type"`
+	AppID     string    `json:"app_id"`
+	Channel   string    `json:"channel,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	IsForced  bool      `json:"is_forced"`
+	Message   string    `json:"message,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ForceUpdateRequest represents the payload for issuing a forced update of a version
+type ForceUpdateRequest struct {
+	Message string `json:"message" binding:"max=500"`
+}
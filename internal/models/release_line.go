@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReleaseLine represents a named release track of an application, e.g. "1.x LTS",
+// containing the versions that satisfy its semantic version range
+type ReleaseLine struct {
+	ID           uint   `json:"id" gorm:"primaryKey"`
+	AppID        string `json:"app_id" gorm:"size:32;not null;uniqueIndex:idx_app_release_line" validate:"required"`
+	Name         string `json:"name" gorm:"size:50;not null;uniqueIndex:idx_app_release_line" validate:"required"`
+	DisplayName  string `json:"display_name" gorm:"size:100"`
+	Description  string `json:"description" gorm:"type:text"`
+	VersionRange string `json:"version_range" gorm:"size:100;not null" validate:"required"`
+	// ClientVersionRange pins clients whose current version satisfies it to this line
+	// when they don't request a line explicitly. Empty means explicit opt-in only.
+	ClientVersionRange string         `json:"client_version_range" gorm:"size:100"`
+	Priority           int            `json:"priority" gorm:"default:0"`
+	IsActive           bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relations
+	Application Application `json:"-" gorm:"foreignKey:AppID;references:AppID"`
+}
+
+// TableName returns the table name for ReleaseLine model
+func (ReleaseLine) TableName() string {
+	return "release_lines"
+}
+
+// ReleaseLineRequest represents the request payload for creating/updating release lines
+type ReleaseLineRequest struct {
+	Name               string `json:"name" validate:"required"`
+	DisplayName        string `json:"display_name"`
+	Description        string `json:"description"`
+	VersionRange       string `json:"version_range" validate:"required"`
+	ClientVersionRange string `json:"client_version_range"`
+	Priority           int    `json:"priority"`
+	IsActive           bool   `json:"is_active"`
+}
+
+// ReleaseLineResponse represents the response payload for release line queries
+type ReleaseLineResponse struct {
+	ID                 uint      `json:"id"`
+	AppID              string    `json:"app_id"`
+	Name               string    `json:"name"`
+	DisplayName        string    `json:"display_name"`
+	Description        string    `json:"description"`
+	VersionRange       string    `json:"version_range"`
+	ClientVersionRange string    `json:"client_version_range"`
+	Priority           int       `json:"priority"`
+	IsActive           bool      `json:"is_active"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// ToResponse converts ReleaseLine model to ReleaseLineResponse
+func (rl *ReleaseLine) ToResponse() *ReleaseLineResponse {
+	return &ReleaseLineResponse{
+		ID:                 rl.ID,
+		AppID:              rl.AppID,
+		Name:               rl.Name,
+		DisplayName:        rl.DisplayName,
+		Description:        rl.Description,
+		VersionRange:       rl.VersionRange,
+		ClientVersionRange: rl.ClientVersionRange,
+		Priority:           rl.Priority,
+		IsActive:           rl.IsActive,
+		CreatedAt:          rl.CreatedAt,
+		UpdatedAt:          rl.UpdatedAt,
+	}
+}
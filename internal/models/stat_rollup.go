@@ -0,0 +1,30 @@
+package models
+
+// StatRollupDateFormat is the format of the date of a stat rollup, days are UTC
+const StatRollupDateFormat = "2006-01-02"
+
+// Actions of the rollups of the distinct clients of a day across versions, regions and
+// actions, which can't be summed from the other rollups without counting clients repeatedly
+const (
+	StatRollupChannelClients = "channel_clients" // Per application and channel
+	StatRollupAppClients     = "app_clients"     // Per application, across channels
+)
+
+// StatRollup represents the update stats of one day, aggregated per application, channel,
+// version, region and action, and the distinct clients of the day
+type StatRollup struct {
+	ID            uint   `json:"id" gorm:"primaryKey"`
+	Date          string `json:"date" gorm:"not null;size:10;index:idx_stat_rollups_date_app,priority:1"`
+	AppID         string `json:"app_id" gorm:"size:32;index:idx_stat_rollups_date_app,priority:2"`
+	Channel       string `json:"channel" gorm:"size:50"`
+	Version       string `json:"version" gorm:"size:50"`
+	Region        string `json:"region" gorm:"size:10"`
+	Action        string `json:"action" gorm:"not null;size:20"`
+	Events        int64  `json:"events"`
+	UniqueClients int64  `json:"unique_clients"` // Distinct client IDs of the day
+}
+
+// TableName returns the table name for StatRollup model
+func (StatRollup) TableName() string {
+	return "stat_rollups"
+}
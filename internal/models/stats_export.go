@@ -0,0 +1,22 @@
+package models
+
+// Formats of the update stats export
+const (
+	StatsExportCSV    = "csv"
+	StatsExportNDJSON = "ndjson"
+)
+
+// StatsExportRequest represents an export of raw update stats. From and To are RFC 3339
+// times or UTC days (YYYY-MM-DD), a day as To includes the whole day. Stats are exported in
+// ID order, Cursor continues an export after the stat with that ID.
+type StatsExportRequest struct {
+	Format  string `form:"format"`
+	AppID   string `form:"app_id"`
+	Channel string `form:"channel"`
+	Action  string `form:"action"`
+	Version string `form:"version"`
+	From    string `form:"from"`
+	To      string `form:"to"`
+	Cursor  uint   `form:"cursor"`
+	Limit   int    `form:"limit"` // Maximum number of stats, 0 exports all
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// Telemetry event types accepted by the batch telemetry endpoint
+const (
+	TelemetryEventDownload = "download"
+	TelemetryEventInstall  = "install"
+
+	// MaxTelemetryBatchSize is the maximum number of events accepted in one batch
+	MaxTelemetryBatchSize = 500
+)
+
+// Telemetry event results
+const (
+	TelemetryEventAccepted  = "accepted"
+	TelemetryEventDuplicate = "duplicate"
+)
+
+// TelemetryEvent represents a download or install event recorded by a client,
+// possibly while it was offline
+type TelemetryEvent struct {
+	EventID      string     `json:"event_id" binding:"required,max=64"` // Client generated, e.g. a UUID
+	Type         string     `json:"type" binding:"required,oneof=download install"`
+	Version      string     `json:"version" binding:"required"`
+	ClientID     string     `json:"client_id"` // Defaults to the client_id of the batch
+	Success      bool       `json:"success"`   // Install events only
+	ErrorMessage string     `json:"error_message"`
+	OccurredAt   *time.Time `json:"occurred_at"` // Client side time of the event, defaults to the time it is received
+}
+
+// TelemetryBatchRequest represents the request payload for recording a batch of telemetry events
+type TelemetryBatchRequest struct {
+	ClientID      string           `json:"client_id"`
+	ClientVersion string           `json:"client_version"`
+	Events        []TelemetryEvent `json:"events" binding:"required,min=1,max=500,dive"`
+}
+
+// TelemetryEventResult reports whether an event was recorded or already known
+type TelemetryEventResult struct {
+	EventID string `json:"event_id"`
+	Status  string `json:"status"` // accepted or duplicate
+}
+
+// TelemetryBatchResponse represents the result of recording a batch of telemetry events
+type TelemetryBatchResponse struct {
+	Accepted   int                    `json:"accepted"`
+	Duplicates int                    `json:"duplicates"`
+	Results    []TelemetryEventResult `json:"results"`
+}
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"time"
+)
+
+// TUF roles and constants, see https://theupdateframework.github.io/specification/latest/
+const (
+	TUFRoleRoot      = "root"
+	TUFRoleTargets   = "targets"
+	TUFRoleSnapshot  = "snapshot"
+	TUFRoleTimestamp = "timestamp"
+
+	TUFSpecVersion    = "1.0.31"
+	TUFKeyTypeEd25519 = "ed25519"
+	TUFExpiresFormat  = "2006-01-02T15:04:05Z"
+)
+
+// TUFRoles lists the top-level TUF roles in signing order
+var TUFRoles = []string{TUFRoleRoot, TUFRoleTargets, TUFRoleSnapshot, TUFRoleTimestamp}
+
+// IsValidTUFRole checks if a role is one of the top-level TUF roles
+func IsValidTUFRole(role string) bool {
+	for _, r := range TUFRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// TUFKey represents an Ed25519 signing key of a TUF role of an application.
+// Rotated keys are kept (inactive) so that old root metadata stays verifiable.
+type TUFKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	AppID      string     `json:"app_id" gorm:"not null;size:50;index:idx_tuf_key_role"`
+	Role       string     `json:"role" gorm:"not null;size:20;index:idx_tuf_key_role"`
+	KeyID      string     `json:"key_id" gorm:"not null;size:64;uniqueIndex"`
+	PublicKey  string     `json:"public_key" gorm:"not null;size:64"` // Hex encoded
+	PrivateKey string     `json:"-" gorm:"not null;type:text"`        // Encrypted with the TUF key secret
+	IsActive   bool       `json:"is_active" gorm:"default:true"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TableName returns the table name for TUFKey model
+func (TUFKey) TableName() string {
+	return "tuf_keys"
+}
+
+// TUFMetadata represents a signed version of the metadata file of a TUF role
+type TUFMetadata struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	AppID     string    `json:"app_id" gorm:"not null;size:50;uniqueIndex:idx_tuf_metadata"`
+	Role      string    `json:"role" gorm:"not null;size:20;uniqueIndex:idx_tuf_metadata"`
+	Version   int       `json:"version" gorm:"not null;uniqueIndex:idx_tuf_metadata"`
+	Expires   time.Time `json:"expires" gorm:"not null"`
+	Content   string    `json:"-" gorm:"not null;type:text"` // Signed canonical JSON as served to clients
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for TUFMetadata model
+func (TUFMetadata) TableName() string {
+	return "tuf_metadata"
+}
+
+// TUFSignedMetadata is the envelope of a TUF metadata file
+type TUFSignedMetadata struct {
+	Signed     interface{}    `json:"signed"`
+	Signatures []TUFSignature `json:"signatures"`
+}
+
+// TUFSignature is a signature of TUF metadata
+type TUFSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// TUFPublicKey is a public key as listed in root metadata
+type TUFPublicKey struct {
+	KeyType string          `json:"keytype"`
+	Scheme  string          `json:"scheme"`
+	KeyVal  TUFPublicKeyVal `json:"keyval"`
+}
+
+// TUFPublicKeyVal holds the hex encoded public key
+type TUFPublicKeyVal struct {
+	Public string `json:"public"`
+}
+
+// TUFRoleKeys lists the keys trusted for a role and how many signatures are required
+type TUFRoleKeys struct {
+	KeyIDs    []string `json:"keyids"`
+	Threshold int      `json:"threshold"`
+}
+
+// TUFRoot is the signed part of root.json
+type TUFRoot struct {
+	Type               string                  `json:"_type"`
+	SpecVersion        string                  `json:"spec_version"`
+	Version            int                     `json:"version"`
+	Expires            string                  `json:"expires"`
+	ConsistentSnapshot bool                    `json:"consistent_snapshot"`
+	Keys               map[string]TUFPublicKey `json:"keys"`
+	Roles              map[string]TUFRoleKeys  `json:"roles"`
+}
+
+// TUFTargets is the signed part of targets.json
+type TUFTargets struct {
+	Type        string                   `json:"_type"`
+	SpecVersion string                   `json:"spec_version"`
+	Version     int                      `json:"version"`
+	Expires     string                   `json:"expires"`
+	Targets     map[string]TUFTargetFile `json:"targets"`
+}
+
+// TUFTargetFile describes a downloadable file in targets.json
+type TUFTargetFile struct {
+	Length int64             `json:"length"`
+	Hashes map[string]string `json:"hashes"`
+	Custom *TUFTargetCustom  `json:"custom,omitempty"`
+}
+
+// TUFTargetCustom links a target file to its VerTree version and download URL
+type TUFTargetCustom struct {
+	Version  string `json:"version"`
+	Channel  string `json:"channel"`
+	OS       string `json:"os,omitempty"`
+	Arch     string `json:"arch,omitempty"`
+	URL      string `json:"url"`
+	IsForced bool   `json:"is_forced"`
+}
+
+// TUFSnapshot is the signed part of snapshot.json
+type TUFSnapshot struct {
+	Type        string                 `json:"_type"`
+	SpecVersion string                 `json:"spec_version"`
+	Version     int                    `json:"version"`
+	Expires     string                 `json:"expires"`
+	Meta        map[string]TUFMetaFile `json:"meta"`
+}
+
+// TUFTimestamp is the signed part of timestamp.json
+type TUFTimestamp struct {
+	Type        string                 `json:"_type"`
+	SpecVersion string                 `json:"spec_version"`
+	Version     int                    `json:"version"`
+	Expires     string                 `json:"expires"`
+	Meta        map[string]TUFMetaFile `json:"meta"`
+}
+
+// TUFMetaFile describes a metadata file referenced by snapshot or timestamp metadata
+type TUFMetaFile struct {
+	Version int               `json:"version"`
+	Length  int64             `json:"length,omitempty"`
+	Hashes  map[string]string `json:"hashes,omitempty"`
+}
+
+// TUFKeyResponse represents the public information of a TUF key
+type TUFKeyResponse struct {
+	Role      string     `json:"role"`
+	KeyID     string     `json:"key_id"`
+	KeyType   string     `json:"key_type"`
+	PublicKey string     `json:"public_key"`
+	IsActive  bool       `json:"is_active"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// ToResponse converts TUFKey model to TUFKeyResponse
+func (k *TUFKey) ToResponse() *TUFKeyResponse {
+	return &TUFKeyResponse{
+		Role:      k.Role,
+		KeyID:     k.KeyID,
+		KeyType:   TUFKeyTypeEd25519,
+		PublicKey: k.PublicKey,
+		IsActive:  k.IsActive,
+		CreatedAt: k.CreatedAt,
+		RevokedAt: k.RevokedAt,
+	}
+}
+
+// TUFMetadataInfo describes the current metadata of a TUF role
+type TUFMetadataInfo struct {
+	Role      string    `json:"role"`
+	Version   int       `json:"version"`
+	Expires   time.Time `json:"expires"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TUFStatusResponse represents the TUF keys and current metadata of an application
+type TUFStatusResponse struct {
+	AppID    string             `json:"app_id"`
+	Keys     []*TUFKeyResponse  `json:"keys"`
+	Metadata []*TUFMetadataInfo `json:"metadata"`
+}
@@ -0,0 +1,54 @@
+package models
+
+// Status of a step of an update decision trace
+const (
+	DecisionStepPassed  = "passed"
+	DecisionStepFailed  = "failed"  // The step ended the decision
+	DecisionStepSkipped = "skipped" // The step doesn't apply to the request
+)
+
+// Outcome of an explained update decision
+const (
+	DecisionOutcomeUpdate   = "update"
+	DecisionOutcomeNoUpdate = "no_update"
+	DecisionOutcomeError    = "error"
+)
+
+// DecisionStep represents one step of an update decision trace
+type DecisionStep struct {
+	Step   string `json:"step"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// DecisionTrace records the steps of an update decision. A nil trace records nothing.
+type DecisionTrace struct {
+	Steps []DecisionStep
+}
+
+// Add appends a step to the trace
+func (t *DecisionTrace) Add(step, status, detail string) {
+	if t == nil {
+		return
+	}
+	t.Steps = append(t.Steps, DecisionStep{Step: step, Status: status, Detail: detail})
+}
+
+// ExplainUpdateRequest represents a simulated update check. Fields of the client's last
+// update check that are left empty are taken from the client inventory.
+type ExplainUpdateRequest struct {
+	CheckUpdateRequest
+	APIVersion string `json:"api_version"` // Client API the check is simulated for: v1 (default) or v2
+}
+
+// ExplainUpdateResponse represents the outcome of a simulated update check with the trace
+// of the decision
+type ExplainUpdateResponse struct {
+	Request      *CheckUpdateRequest  `json:"request"` // The simulated request after filling in inventory data
+	Outcome      string               `json:"outcome"` // update, no_update or error
+	Reason       string               `json:"reason,omitempty"`
+	ReasonDetail string               `json:"reason_detail,omitempty"`
+	ErrorCode    string               `json:"error_code,omitempty"`
+	Response     *CheckUpdateResponse `json:"response,omitempty"` // What the client would receive
+	Steps        []DecisionStep       `json:"steps"`
+}
@@ -1,25 +1,29 @@
 package models
 
 import (
-	"time"
-	"net"
 	"gorm.io/gorm"
+	"net"
+	"time"
 )
 
 // UpdateStat represents an update statistic record in the database
 type UpdateStat struct {
-	ID            uint           `json:"id" gorm:"primaryKey"`
-	Version       string         `json:"version" gorm:"not null;size:50;index" validate:"required"`
-	ClientID      string         `json:"client_id" gorm:"size:128;index"`
-	ClientVersion string         `json:"client_version" gorm:"size:50"`
-	Region        string         `json:"region" gorm:"size:10"`
-	IPAddress     net.IP         `json:"ip_address" gorm:"type:inet"`
-	UserAgent     string         `json:"user_agent" gorm:"type:text"`
-	Action        string         `json:"action" gorm:"not null;size:20;index" validate:"required,oneof=check download install success failed"`
-	ErrorMessage  string         `json:"error_message" gorm:"type:text"`
-	CreatedAt     time.Time      `json:"created_at" gorm:"index"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	AppID          string         `json:"app_id" gorm:"size:32;index;uniqueIndex:idx_update_stats_app_client_event,priority:1"`
+	Channel        string         `json:"channel" gorm:"size:50;index"`
+	Version        string         `json:"version" gorm:"not null;size:50;index" validate:"required"`
+	ClientID       string         `json:"client_id" gorm:"size:128;index;uniqueIndex:idx_update_stats_app_client_event,priority:2"`
+	ClientVersion  string         `json:"client_version" gorm:"size:50"`
+	Region         string         `json:"region" gorm:"size:10"`
+	IPAddress      net.IP         `json:"ip_address" gorm:"type:inet"`
+	UserAgent      string         `json:"user_agent" gorm:"type:text"`
+	Action         string         `json:"action" gorm:"not null;size:20;index" validate:"required,oneof=check offer download install success failed"`
+	ErrorMessage   string         `json:"error_message" gorm:"type:text"`
+	ErrorSignature string         `json:"error_signature" gorm:"size:255;index"`                                                      // Normalized ErrorMessage, see utils.ErrorSignature
+	EventID        *string        `json:"event_id,omitempty" gorm:"size:64;uniqueIndex:idx_update_stats_app_client_event,priority:3"` // Client generated, unique per app and client, set for batch telemetry
+	CreatedAt      time.Time      `json:"created_at" gorm:"index"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // TableName returns the table name for UpdateStat model
@@ -29,41 +33,56 @@ func (UpdateStat) TableName() string {
 
 // UpdateStatRequest represents the request payload for creating update statistics
 type UpdateStatRequest struct {
+	AppID         string `json:"app_id"`
+	Channel       string `json:"channel"` // Defaults to the channel of the version
 	Version       string `json:"version" validate:"required"`
 	ClientID      string `json:"client_id"`
 	ClientVersion string `json:"client_version"`
 	Region        string `json:"region"`
 	UserAgent     string `json:"user_agent"`
-	Action        string `json:"action" validate:"required,oneof=check download install success failed"`
+	Action        string `json:"action" validate:"required,oneof=check offer download install success failed"`
 	ErrorMessage  string `json:"error_message"`
 }
 
 // UpdateStatResponse represents the response payload for update stat queries
 type UpdateStatResponse struct {
-	ID            uint      `json:"id"`
-	Version       string    `json:"version"`
-	ClientID      string    `json:"client_id"`
-	ClientVersion string    `json:"client_version"`
-	Region        string    `json:"region"`
-	IPAddress     string    `json:"ip_address"`
-	UserAgent     string    `json:"user_agent"`
-	Action        string    `json:"action"`
-	ErrorMessage  string    `json:"error_message"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID             uint      `json:"id"`
+	AppID          string    `json:"app_id"`
+	Channel        string    `json:"channel"`
+	Version        string    `json:"version"`
+	ClientID       string    `json:"client_id"`
+	ClientVersion  string    `json:"client_version"`
+	Region         string    `json:"region"`
+	IPAddress      string    `json:"ip_address"`
+	UserAgent      string    `json:"user_agent"`
+	Action         string    `json:"action"`
+	ErrorMessage   string    `json:"error_message"`
+	ErrorSignature string    `json:"error_signature,omitempty"`
+	EventID        string    `json:"event_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
 // ToResponse converts UpdateStat model to UpdateStatResponse
 func (us *UpdateStat) ToResponse() *UpdateStatResponse {
+	eventID := ""
+	if us.EventID != nil {
+		eventID = *us.EventID
+	}
+
 	return &UpdateStatResponse{
-		ID:            us.ID,
-		Version:       us.Version,
-		ClientID:      us.ClientID,
-		ClientVersion: us.ClientVersion,
-		Region:        us.Region,
-		IPAddress:     us.IPAddress.String(),
-		UserAgent:     us.UserAgent,
-		Action:        us.Action,
-		ErrorMessage:  us.ErrorMessage,
-		CreatedAt:     us.CreatedAt,
+		ID:             us.ID,
+		AppID:          us.AppID,
+		Channel:        us.Channel,
+		Version:        us.Version,
+		ClientID:       us.ClientID,
+		ClientVersion:  us.ClientVersion,
+		Region:         us.Region,
+		IPAddress:      us.IPAddress.String(),
+		UserAgent:      us.UserAgent,
+		Action:         us.Action,
+		ErrorMessage:   us.ErrorMessage,
+		ErrorSignature: us.ErrorSignature,
+		EventID:        eventID,
+		CreatedAt:      us.CreatedAt,
 	}
 }
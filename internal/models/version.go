@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,27 +9,40 @@ import (
 
 // Version represents a software version in the database
 type Version struct {
-	ID                uint           `json:"id" gorm:"primaryKey"`
-	AppID             string         `json:"app_id" gorm:"size:32;uniqueIndex:idx_app_version" validate:"required"`
-	Version           string         `json:"version" gorm:"not null;size:50;uniqueIndex:idx_app_version" validate:"required"`
-	Channel           string         `json:"channel" gorm:"not null;size:20;default:stable" validate:"required"`
-	Title             string         `json:"title" gorm:"not null;size:200" validate:"required"`
-	Description       string         `json:"description" gorm:"type:text"`
-	ReleaseNotes      string         `json:"release_notes" gorm:"type:text"`
-	BreakingChanges   string         `json:"breaking_changes" gorm:"type:text"`
-	MinUpgradeVersion string         `json:"min_upgrade_version" gorm:"size:50"`
-	FileURL           string         `json:"file_url" gorm:"not null;size:500" validate:"required,url"`
-	FileSize          int64          `json:"file_size" gorm:"not null" validate:"required,min=1"`
-	FileChecksum      string         `json:"file_checksum" gorm:"not null;size:128" validate:"required"`
-	IsPublished       bool           `json:"is_published" gorm:"default:false"`
-	IsForced          bool           `json:"is_forced" gorm:"default:false"`
-	PublishTime       *time.Time     `json:"publish_time"`
-	CreatedAt         time.Time      `json:"created_at"`
-	UpdatedAt         time.Time      `json:"updated_at"`
-	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                uint       `json:"id" gorm:"primaryKey"`
+	AppID             string     `json:"app_id" gorm:"size:32;uniqueIndex:idx_app_version" validate:"required"`
+	Version           string     `json:"version" gorm:"not null;size:50;uniqueIndex:idx_app_version" validate:"required"`
+	Channel           string     `json:"channel" gorm:"not null;size:20;default:stable" validate:"required"`
+	Title             string     `json:"title" gorm:"not null;size:200" validate:"required"`
+	Description       string     `json:"description" gorm:"type:text"`
+	ReleaseNotes      string     `json:"release_notes" gorm:"type:text"`
+	BreakingChanges   string     `json:"breaking_changes" gorm:"type:text"`
+	MinUpgradeVersion string     `json:"min_upgrade_version" gorm:"size:50"`
+	FileURL           string     `json:"file_url" gorm:"not null;size:500" validate:"required,url"`
+	FileSize          int64      `json:"file_size" gorm:"not null" validate:"required,min=1"`
+	FileChecksum      string     `json:"file_checksum" gorm:"not null;size:128" validate:"required"`
+	IsPublished       bool       `json:"is_published" gorm:"default:false"`
+	IsForced          bool       `json:"is_forced" gorm:"default:false"`
+	PublishTime       *time.Time `json:"publish_time"`
+
+	// Compatibility constraints evaluated against the client during update checks
+	MinOSVersion         string         `json:"min_os_version" gorm:"size:50"`
+	MaxOSVersion         string         `json:"max_os_version" gorm:"size:50"`
+	RequiredDependencies DependencyList `json:"required_dependencies" gorm:"type:json"`
+	RequiredCapabilities StringList     `json:"required_capabilities" gorm:"type:json"`
+
+	// Support window of the version once it is in the field
+	DeprecatedAt *time.Time `json:"deprecated_at"`
+	EOLAt        *time.Time `json:"eol_at" gorm:"column:eol_at"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relations
-	Application Application `json:"application,omitempty" gorm:"foreignKey:AppID;references:AppID"`
+	Application   Application           `json:"application,omitempty" gorm:"foreignKey:AppID;references:AppID"`
+	Localizations []VersionLocalization `json:"-" gorm:"foreignKey:VersionID"`
+	Artifacts     []VersionArtifact     `json:"artifacts,omitempty" gorm:"foreignKey:VersionID"`
 }
 
 // TableName returns the table name for Version model
@@ -36,6 +50,50 @@ func (Version) TableName() string {
 	return "versions"
 }
 
+// ApplyLocalization overlays the non-empty fields of a localization onto the version
+func (v *Version) ApplyLocalization(l *VersionLocalization) {
+	if l == nil {
+		return
+	}
+	if l.Title != "" {
+		v.Title = l.Title
+	}
+	if l.Description != "" {
+		v.Description = l.Description
+	}
+	if l.ReleaseNotes != "" {
+		v.ReleaseNotes = l.ReleaseNotes
+	}
+	if l.BreakingChanges != "" {
+		v.BreakingChanges = l.BreakingChanges
+	}
+}
+
+// SupportWindowRequest represents the request payload for updating a version's support window
+type SupportWindowRequest struct {
+	DeprecatedAt *time.Time `json:"deprecated_at"`
+	EOLAt        *time.Time `json:"eol_at"`
+}
+
+// Support status values reported to clients for their current version
+const (
+	SupportStatusSupported  = "supported"
+	SupportStatusDeprecated = "deprecated"
+	SupportStatusEOL        = "eol"
+	SupportStatusUnknown    = "unknown"
+)
+
+// SupportStatus returns the support status of the version at the given time
+func (v *Version) SupportStatus(now time.Time) string {
+	if v.EOLAt != nil && !now.Before(*v.EOLAt) {
+		return SupportStatusEOL
+	}
+	if v.DeprecatedAt != nil && !now.Before(*v.DeprecatedAt) {
+		return SupportStatusDeprecated
+	}
+	return SupportStatusSupported
+}
+
 // VersionRequest represents the request payload for creating/updating versions
 type VersionRequest struct {
 	AppID             string `json:"app_id" validate:"required"`
@@ -50,6 +108,14 @@ type VersionRequest struct {
 	FileSize          int64  `json:"file_size" validate:"required,min=1"`
 	FileChecksum      string `json:"file_checksum" validate:"required"`
 	IsForced          bool   `json:"is_forced"`
+
+	MinOSVersion         string         `json:"min_os_version"`
+	MaxOSVersion         string         `json:"max_os_version"`
+	RequiredDependencies DependencyList `json:"required_dependencies"`
+	RequiredCapabilities StringList     `json:"required_capabilities"`
+
+	DeprecatedAt *time.Time `json:"deprecated_at"`
+	EOLAt        *time.Time `json:"eol_at"`
 }
 
 // VersionResponse represents the response payload for version queries
@@ -69,13 +135,24 @@ type VersionResponse struct {
 	IsPublished       bool       `json:"is_published"`
 	IsForced          bool       `json:"is_forced"`
 	PublishTime       *time.Time `json:"publish_time"`
-	CreatedAt         time.Time  `json:"created_at"`
-	UpdatedAt         time.Time  `json:"updated_at"`
+
+	MinOSVersion         string         `json:"min_os_version"`
+	MaxOSVersion         string         `json:"max_os_version"`
+	RequiredDependencies DependencyList `json:"required_dependencies"`
+	RequiredCapabilities StringList     `json:"required_capabilities"`
+
+	DeprecatedAt *time.Time `json:"deprecated_at"`
+	EOLAt        *time.Time `json:"eol_at"`
+
+	Artifacts []*VersionArtifactResponse `json:"artifacts,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // ToResponse converts Version model to VersionResponse
 func (v *Version) ToResponse() *VersionResponse {
-	return &VersionResponse{
+	response := &VersionResponse{
 		ID:                v.ID,
 		AppID:             v.AppID,
 		Version:           v.Version,
@@ -91,7 +168,45 @@ func (v *Version) ToResponse() *VersionResponse {
 		IsPublished:       v.IsPublished,
 		IsForced:          v.IsForced,
 		PublishTime:       v.PublishTime,
-		CreatedAt:         v.CreatedAt,
-		UpdatedAt:         v.UpdatedAt,
+
+		MinOSVersion:         v.MinOSVersion,
+		MaxOSVersion:         v.MaxOSVersion,
+		RequiredDependencies: v.RequiredDependencies,
+		RequiredCapabilities: v.RequiredCapabilities,
+
+		DeprecatedAt: v.DeprecatedAt,
+		EOLAt:        v.EOLAt,
+
+		CreatedAt: v.CreatedAt,
+		UpdatedAt: v.UpdatedAt,
+	}
+
+	for i := range v.Artifacts {
+		response.Artifacts = append(response.Artifacts, v.Artifacts[i].ToResponse())
+	}
+
+	return response
+}
+
+// FindArtifact returns the artifact built for the given OS and architecture, falling back
+// to an artifact for the OS with no specific architecture. Returns nil if none matches.
+func (v *Version) FindArtifact(os, arch string) *VersionArtifact {
+	if os == "" {
+		return nil
+	}
+
+	var fallback *VersionArtifact
+	for i := range v.Artifacts {
+		artifact := &v.Artifacts[i]
+		if !strings.EqualFold(artifact.OS, os) {
+			continue
+		}
+		if strings.EqualFold(artifact.Arch, arch) {
+			return artifact
+		}
+		if artifact.Arch == "" || strings.EqualFold(artifact.Arch, "universal") {
+			fallback = artifact
+		}
 	}
+	return fallback
 }
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// VersionArtifact represents a platform-specific download of a version, e.g. the
+// darwin/arm64 build. The file fields of the Version itself remain the default download.
+type VersionArtifact struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	VersionID    uint           `json:"version_id" gorm:"not null;uniqueIndex:idx_version_artifact"`
+	OS           string         `json:"os" gorm:"size:20;uniqueIndex:idx_version_artifact"`
+	Arch         string         `json:"arch" gorm:"size:20;uniqueIndex:idx_version_artifact"`
+	FileName     string         `json:"file_name" gorm:"size:255"`
+	FileURL      string         `json:"file_url" gorm:"not null;size:500"`
+	FileSize     int64          `json:"file_size" gorm:"not null"`
+	FileChecksum string         `json:"file_checksum" gorm:"not null;size:128"`
+	Signature    string         `json:"signature" gorm:"type:text"`
+	SHA512       string         `json:"sha512" gorm:"column:sha512;size:100"` // Base64 SHA512, as used by electron-updater
+	SHA1         string         `json:"sha1" gorm:"column:sha1;size:40"`      // Hex SHA1, as used by Squirrel.Windows
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for VersionArtifact model
+func (VersionArtifact) TableName() string {
+	return "version_artifacts"
+}
+
+// VersionArtifactRequest represents the payload describing a version artifact
+type VersionArtifactRequest struct {
+	OS           string `json:"os" yaml:"os"`
+	Arch         string `json:"arch" yaml:"arch"`
+	FileName     string `json:"file_name" yaml:"file_name"`
+	FileURL      string `json:"file_url" yaml:"file_url"`
+	FileSize     int64  `json:"file_size" yaml:"file_size"`
+	FileChecksum string `json:"file_checksum" yaml:"file_checksum"`
+	Signature    string `json:"signature" yaml:"signature"`
+	SHA512       string `json:"sha512" yaml:"sha512"`
+	SHA1         string `json:"sha1" yaml:"sha1"`
+}
+
+// VersionArtifactResponse represents the response payload for version artifacts
+type VersionArtifactResponse struct {
+	ID           uint   `json:"id"`
+	OS           string `json:"os"`
+	Arch         string `json:"arch"`
+	FileName     string `json:"file_name"`
+	FileURL      string `json:"file_url"`
+	FileSize     int64  `json:"file_size"`
+	FileChecksum string `json:"file_checksum"`
+	Signature    string `json:"signature,omitempty"`
+	SHA512       string `json:"sha512,omitempty"`
+	SHA1         string `json:"sha1,omitempty"`
+}
+
+// ToResponse converts VersionArtifact model to VersionArtifactResponse
+func (a *VersionArtifact) ToResponse() *VersionArtifactResponse {
+	return &VersionArtifactResponse{
+		ID:           a.ID,
+		OS:           a.OS,
+		Arch:         a.Arch,
+		FileName:     a.FileName,
+		FileURL:      a.FileURL,
+		FileSize:     a.FileSize,
+		FileChecksum: a.FileChecksum,
+		Signature:    a.Signature,
+		SHA512:       a.SHA512,
+		SHA1:         a.SHA1,
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// VersionLocalization represents a per-locale variant of a version's release information
+type VersionLocalization struct {
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	VersionID       uint           `json:"version_id" gorm:"not null;uniqueIndex:idx_version_locale" validate:"required"`
+	Locale          string         `json:"locale" gorm:"not null;size:20;uniqueIndex:idx_version_locale" validate:"required"`
+	Title           string         `json:"title" gorm:"size:200"`
+	Description     string         `json:"description" gorm:"type:text"`
+	ReleaseNotes    string         `json:"release_notes" gorm:"type:text"`
+	BreakingChanges string         `json:"breaking_changes" gorm:"type:text"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for VersionLocalization model
+func (VersionLocalization) TableName() string {
+	return "version_localizations"
+}
+
+// VersionLocalizationRequest represents the request payload for creating/updating a localization
+type VersionLocalizationRequest struct {
+	Title           string `json:"title"`
+	Description     string `json:"description"`
+	ReleaseNotes    string `json:"release_notes"`
+	BreakingChanges string `json:"breaking_changes"`
+}
+
+// VersionLocalizationResponse represents the response payload for localization queries
+type VersionLocalizationResponse struct {
+	ID              uint      `json:"id"`
+	VersionID       uint      `json:"version_id"`
+	Locale          string    `json:"locale"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	ReleaseNotes    string    `json:"release_notes"`
+	BreakingChanges string    `json:"breaking_changes"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ToResponse converts VersionLocalization model to VersionLocalizationResponse
+func (vl *VersionLocalization) ToResponse() *VersionLocalizationResponse {
+	return &VersionLocalizationResponse{
+		ID:              vl.ID,
+		VersionID:       vl.VersionID,
+		Locale:          vl.Locale,
+		Title:           vl.Title,
+		Description:     vl.Description,
+		ReleaseNotes:    vl.ReleaseNotes,
+		BreakingChanges: vl.BreakingChanges,
+		CreatedAt:       vl.CreatedAt,
+		UpdatedAt:       vl.UpdatedAt,
+	}
+}
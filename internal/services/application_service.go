@@ -39,6 +39,7 @@ func (s *ApplicationService) CreateApplication(req *models.ApplicationRequest, a
 		Description: req.Description,
 		Icon:        req.Icon,
 		IsActive:    req.IsActive,
+		IsPublic:    req.IsPublic,
 		CreatedBy:   adminID,
 	}
 
@@ -159,6 +160,7 @@ func (s *ApplicationService) UpdateApplication(appID string, req *models.Applica
 	app.Description = req.Description
 	app.Icon = req.Icon
 	app.IsActive = req.IsActive
+	app.IsPublic = req.IsPublic
 
 	if err := s.db.Save(&app).Error; err != nil {
 		return nil, fmt.Errorf("failed to update application: %w", err)
@@ -377,3 +379,64 @@ func (s *ApplicationService) ValidateAPIKey(appID, keySecret string) (*models.Ap
 
 	return &app, &key, nil
 }
+
+// GetPublicApplication retrieves an active application that allows anonymous update checks
+func (s *ApplicationService) GetPublicApplication(appID string) (*models.Application, error) {
+	var app models.Application
+	if err := s.db.Where("app_id = ? AND is_active = ? AND is_public = ?", appID, true, true).First(&app).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("public application not found")
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	return &app, nil
+}
+
+// RotateFeedToken generates a new feed token for an application, invalidating the previous
+// one. The token is only returned here; just its hash is stored.
+func (s *ApplicationService) RotateFeedToken(appID string) (*models.FeedTokenResponse, error) {
+	var app models.Application
+	if err := s.db.Where("app_id = ?", appID).First(&app).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("application not found")
+		}
+		return nil, fmt.Errorf("failed to find application: %w", err)
+	}
+
+	token, err := models.GenerateKeySecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate feed token: %w", err)
+	}
+
+	hasher := sha256.New()
+	hasher.Write([]byte(token))
+	tokenHash := hex.EncodeToString(hasher.Sum(nil))
+
+	if err := s.db.Model(&app).Update("feed_token_hash", tokenHash).Error; err != nil {
+		return nil, fmt.Errorf("failed to save feed token: %w", err)
+	}
+
+	return &models.FeedTokenResponse{
+		AppID:     app.AppID,
+		FeedToken: token,
+	}, nil
+}
+
+// ValidateFeedToken validates the feed token of an application and returns the application
+func (s *ApplicationService) ValidateFeedToken(appID, token string) (*models.Application, error) {
+	if token == "" {
+		return nil, fmt.Errorf("invalid feed token")
+	}
+
+	hasher := sha256.New()
+	hasher.Write([]byte(token))
+	tokenHash := hex.EncodeToString(hasher.Sum(nil))
+
+	var app models.Application
+	if err := s.db.Where("app_id = ? AND is_active = ? AND feed_token_hash = ?", appID, true, tokenHash).First(&app).Error; err != nil {
+		return nil, fmt.Errorf("invalid feed token")
+	}
+
+	return &app, nil
+}
@@ -0,0 +1,674 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/database"
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/utils"
+	"gorm.io/gorm"
+)
+
+// maxBundleFileSize limits the size of a file extracted from a bundle, see SetBundleMaxFileSize
+var maxBundleFileSize int64 = 2 << 30
+
+// SetBundleMaxFileSize sets the largest file in bytes extracted from an imported bundle
+func SetBundleMaxFileSize(size int64) {
+	if size > 0 {
+		maxBundleFileSize = size
+	}
+}
+
+// BundleService handles export and import of whole applications between instances
+type BundleService struct {
+	db         *gorm.DB
+	appSvc     *ApplicationService
+	versionSvc *VersionService
+	lineSvc    *ReleaseLineService
+	versionCmp *utils.VersionComparer
+}
+
+// NewBundleService creates a new bundle service instance
+func NewBundleService() *BundleService {
+	return &BundleService{
+		db:         database.DB,
+		appSvc:     NewApplicationService(),
+		versionSvc: NewVersionService(),
+		lineSvc:    NewReleaseLineService(),
+		versionCmp: utils.NewVersionComparer(),
+	}
+}
+
+// ExportApplication collects the metadata, channel settings, release lines and versions
+// of an application into a bundle
+func (s *BundleService) ExportApplication(appID string) (*models.ApplicationBundle, error) {
+	app, err := s.appSvc.GetApplication(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &models.ApplicationBundle{
+		FormatVersion: models.BundleFormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Application: models.BundleApplication{
+			AppID:       app.AppID,
+			Name:        app.Name,
+			Description: app.Description,
+			Icon:        app.Icon,
+			IsActive:    app.IsActive,
+			IsPublic:    app.IsPublic,
+		},
+		Channels:     []models.BundleChannel{},
+		ReleaseLines: []models.ReleaseLineRequest{},
+		Versions:     []models.BundleVersion{},
+		Files:        []string{},
+	}
+
+	var appChannels []models.ApplicationChannel
+	if err := s.db.Preload("Channel").Where("app_id = ?", app.AppID).Find(&appChannels).Error; err != nil {
+		return nil, fmt.Errorf("failed to get application channels: %w", err)
+	}
+	for _, ac := range appChannels {
+		bundle.Channels = append(bundle.Channels, models.BundleChannel{
+			ChannelRequest: models.ChannelRequest{
+				Name:        ac.ChannelName,
+				DisplayName: ac.Channel.DisplayName,
+				Description: ac.Channel.Description,
+				IsActive:    ac.Channel.IsActive,
+				SortOrder:   ac.Channel.SortOrder,
+			},
+			IsEnabled:         ac.IsEnabled,
+			AutoPublish:       ac.AutoPublish,
+			RolloutPercentage: ac.RolloutPercentage,
+			CheckInterval:     ac.CheckInterval,
+		})
+	}
+
+	lines, err := s.lineSvc.GetReleaseLines(app.AppID)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		bundle.ReleaseLines = append(bundle.ReleaseLines, models.ReleaseLineRequest{
+			Name:               line.Name,
+			DisplayName:        line.DisplayName,
+			Description:        line.Description,
+			VersionRange:       line.VersionRange,
+			ClientVersionRange: line.ClientVersionRange,
+			Priority:           line.Priority,
+			IsActive:           line.IsActive,
+		})
+	}
+
+	var versions []models.Version
+	if err := s.db.Preload("Artifacts").Preload("Localizations").
+		Where("app_id = ?", app.AppID).
+		Order("id").
+		Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get versions: %w", err)
+	}
+
+	files := make(map[string]bool)
+	addFile := func(fileURL string) {
+		if isUploadedFile(fileURL) && !files[fileURL] {
+			files[fileURL] = true
+			bundle.Files = append(bundle.Files, fileURL)
+		}
+	}
+
+	for _, version := range versions {
+		bv := models.BundleVersion{
+			ImportVersion: models.ImportVersion{
+				Version:              version.Version,
+				Channel:              version.Channel,
+				Title:                version.Title,
+				Description:          version.Description,
+				ReleaseNotes:         version.ReleaseNotes,
+				BreakingChanges:      version.BreakingChanges,
+				MinUpgradeVersion:    version.MinUpgradeVersion,
+				FileURL:              version.FileURL,
+				FileSize:             version.FileSize,
+				FileChecksum:         version.FileChecksum,
+				IsForced:             version.IsForced,
+				IsPublished:          version.IsPublished,
+				PublishTime:          version.PublishTime,
+				MinOSVersion:         version.MinOSVersion,
+				MaxOSVersion:         version.MaxOSVersion,
+				RequiredDependencies: version.RequiredDependencies,
+				RequiredCapabilities: version.RequiredCapabilities,
+			},
+			DeprecatedAt: version.DeprecatedAt,
+			EOLAt:        version.EOLAt,
+		}
+		addFile(version.FileURL)
+
+		for _, artifact := range version.Artifacts {
+			bv.Artifacts = append(bv.Artifacts, models.VersionArtifactRequest{
+				OS:           artifact.OS,
+				Arch:         artifact.Arch,
+				FileName:     artifact.FileName,
+				FileURL:      artifact.FileURL,
+				FileSize:     artifact.FileSize,
+				FileChecksum: artifact.FileChecksum,
+				Signature:    artifact.Signature,
+				SHA512:       artifact.SHA512,
+				SHA1:         artifact.SHA1,
+			})
+			addFile(artifact.FileURL)
+		}
+
+		for _, localization := range version.Localizations {
+			bv.Localizations = append(bv.Localizations, models.BundleLocalization{
+				Locale: localization.Locale,
+				VersionLocalizationRequest: models.VersionLocalizationRequest{
+					Title:           localization.Title,
+					Description:     localization.Description,
+					ReleaseNotes:    localization.ReleaseNotes,
+					BreakingChanges: localization.BreakingChanges,
+				},
+			})
+		}
+
+		bundle.Versions = append(bundle.Versions, bv)
+	}
+
+	return bundle, nil
+}
+
+// WriteBundle writes a bundle as a zip archive. When includeFiles is set, the files
+// uploaded to this instance and referenced by the bundle are added to the archive.
+func (s *BundleService) WriteBundle(w io.Writer, bundle *models.ApplicationBundle, includeFiles bool) error {
+	archive := zip.NewWriter(w)
+
+	manifest, err := archive.Create(models.BundleManifestName)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle manifest: %w", err)
+	}
+	encoder := json.NewEncoder(manifest)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bundle); err != nil {
+		return fmt.Errorf("failed to write bundle manifest: %w", err)
+	}
+
+	if includeFiles {
+		for _, fileURL := range bundle.Files {
+			if err := addFileToArchive(archive, fileURL); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finalize bundle: %w", err)
+	}
+	return nil
+}
+
+// addFileToArchive copies an uploaded file into the files directory of the archive
+func addFileToArchive(archive *zip.Writer, fileURL string) error {
+	src, err := os.Open(uploadedFilePath(fileURL))
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Printf("Bundle export: skipping missing file %s", fileURL)
+			return nil
+		}
+		return fmt.Errorf("failed to open file %s: %w", fileURL, err)
+	}
+	defer src.Close()
+
+	dst, err := archive.Create(models.BundleFilesDir + strings.TrimPrefix(fileURL, "/uploads/"))
+	if err != nil {
+		return fmt.Errorf("failed to add file %s to bundle: %w", fileURL, err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("failed to copy file %s to bundle: %w", fileURL, err)
+	}
+	return nil
+}
+
+// ReadBundle reads the manifest of a bundle archive
+func (s *BundleService) ReadBundle(archive *zip.Reader) (*models.ApplicationBundle, error) {
+	for _, file := range archive.File {
+		if file.Name != models.BundleManifestName {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open bundle manifest: %w", err)
+		}
+		defer rc.Close()
+
+		var bundle models.ApplicationBundle
+		if err := json.NewDecoder(rc).Decode(&bundle); err != nil {
+			return nil, fmt.Errorf("failed to parse bundle manifest: %w", err)
+		}
+		if bundle.FormatVersion != models.BundleFormatVersion {
+			return nil, fmt.Errorf("unsupported bundle format version: %d", bundle.FormatVersion)
+		}
+		return &bundle, nil
+	}
+
+	return nil, fmt.Errorf("bundle manifest %s not found", models.BundleManifestName)
+}
+
+// ImportBundle recreates the application of a bundle archive on this instance. If the
+// application already exists (matched by app ID, then by name), onConflict decides whether
+// the import fails, only adds what is missing, or overwrites the existing data.
+// The database changes are applied in a single transaction before any file is restored.
+func (s *BundleService) ImportBundle(archive *zip.Reader, onConflict string, adminID uint) (*models.BundleImportReport, error) {
+	if onConflict == "" {
+		onConflict = models.BundleConflictFail
+	}
+	switch onConflict {
+	case models.BundleConflictFail, models.BundleConflictMerge, models.BundleConflictOverwrite:
+	default:
+		return nil, fmt.Errorf("invalid conflict mode: %s", onConflict)
+	}
+
+	bundle, err := s.ReadBundle(archive)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateBundle(bundle); err != nil {
+		return nil, err
+	}
+	if err := checkBundleFiles(archive); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findExistingApplication(&bundle.Application)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && onConflict == models.BundleConflictFail {
+		return nil, fmt.Errorf("application %s already exists", existing.Name)
+	}
+
+	overwrite := onConflict == models.BundleConflictOverwrite
+	report := &models.BundleImportReport{}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		app, err := s.importApplication(tx, bundle, existing, overwrite, adminID)
+		if err != nil {
+			return err
+		}
+		report.AppID = app.AppID
+		report.Name = app.Name
+		switch {
+		case existing == nil:
+			report.Action = "created"
+		case overwrite:
+			report.Action = "overwritten"
+		default:
+			report.Action = "merged"
+		}
+
+		if report.ChannelsApplied, err = s.importChannels(tx, app.AppID, bundle.Channels, overwrite); err != nil {
+			return err
+		}
+		if report.LinesApplied, err = s.importReleaseLines(tx, app.AppID, bundle.ReleaseLines, overwrite); err != nil {
+			return err
+		}
+		return s.importVersions(tx, app.AppID, bundle.Versions, overwrite, report)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := restoreBundleFiles(archive, overwrite)
+	if err != nil {
+		return nil, err
+	}
+	report.FilesRestored = restored
+
+	s.versionSvc.refreshTUFMetadata(report.AppID)
+
+	return report, nil
+}
+
+// validateBundle validates the application and versions of a bundle before importing it
+func (s *BundleService) validateBundle(bundle *models.ApplicationBundle) error {
+	if bundle.Application.Name == "" {
+		return fmt.Errorf("bundle application name is required")
+	}
+
+	seen := make(map[string]bool)
+	for _, version := range bundle.Versions {
+		if !s.versionCmp.IsValidSemVer(version.Version) {
+			return fmt.Errorf("invalid semantic version in bundle: %s", version.Version)
+		}
+		if seen[version.Version] {
+			return fmt.Errorf("duplicate version in bundle: %s", version.Version)
+		}
+		seen[version.Version] = true
+
+		if err := s.versionSvc.validateConstraints(&models.VersionRequest{
+			MinOSVersion:         version.MinOSVersion,
+			MaxOSVersion:         version.MaxOSVersion,
+			RequiredDependencies: version.RequiredDependencies,
+			DeprecatedAt:         version.DeprecatedAt,
+			EOLAt:                version.EOLAt,
+		}); err != nil {
+			return fmt.Errorf("version %s: %w", version.Version, err)
+		}
+	}
+
+	for _, line := range bundle.ReleaseLines {
+		if err := s.lineSvc.validateRequest(&line); err != nil {
+			return fmt.Errorf("release line %s: %w", line.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// findExistingApplication finds the local application matching a bundled one
+func (s *BundleService) findExistingApplication(bundled *models.BundleApplication) (*models.Application, error) {
+	var app models.Application
+	err := s.db.Where("app_id = ?", bundled.AppID).First(&app).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = s.db.Where("name = ?", bundled.Name).First(&app).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find application: %w", err)
+	}
+	return &app, nil
+}
+
+// importApplication creates the bundled application, keeping its app ID when it is free
+// so that clients keep working, or updates the existing one when overwriting
+func (s *BundleService) importApplication(tx *gorm.DB, bundle *models.ApplicationBundle, existing *models.Application, overwrite bool, adminID uint) (*models.Application, error) {
+	if existing != nil {
+		if overwrite {
+			existing.Description = bundle.Application.Description
+			existing.Icon = bundle.Application.Icon
+			existing.IsActive = bundle.Application.IsActive
+			existing.IsPublic = bundle.Application.IsPublic
+			if err := tx.Save(existing).Error; err != nil {
+				return nil, fmt.Errorf("failed to update application: %w", err)
+			}
+		}
+		return existing, nil
+	}
+
+	app := &models.Application{
+		Name:        bundle.Application.Name,
+		Description: bundle.Application.Description,
+		Icon:        bundle.Application.Icon,
+		IsActive:    bundle.Application.IsActive,
+		IsPublic:    bundle.Application.IsPublic,
+		CreatedBy:   adminID,
+	}
+
+	// Soft-deleted applications still hold their app ID
+	var count int64
+	if err := tx.Unscoped().Model(&models.Application{}).Where("app_id = ?", bundle.Application.AppID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to check app ID: %w", err)
+	}
+	if count == 0 {
+		app.AppID = bundle.Application.AppID
+	}
+
+	if err := tx.Create(app).Error; err != nil {
+		return nil, fmt.Errorf("failed to create application: %w", err)
+	}
+	// Create replaces false by the column default
+	if err := tx.Model(app).Update("is_active", bundle.Application.IsActive).Error; err != nil {
+		return nil, fmt.Errorf("failed to update application: %w", err)
+	}
+	return app, nil
+}
+
+// importChannels applies the bundled channel settings, creating missing global channels
+func (s *BundleService) importChannels(tx *gorm.DB, appID string, channels []models.BundleChannel, overwrite bool) (int, error) {
+	applied := 0
+	for _, bc := range channels {
+		var channel models.Channel
+		if err := tx.Where("name = ?", bc.Name).First(&channel).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			channel = models.Channel{
+				Name:        bc.Name,
+				DisplayName: bc.DisplayName,
+				Description: bc.Description,
+				IsActive:    bc.IsActive,
+				SortOrder:   bc.SortOrder,
+			}
+			if channel.DisplayName == "" {
+				channel.DisplayName = bc.Name
+			}
+			if err := tx.Create(&channel).Error; err != nil {
+				return 0, fmt.Errorf("failed to create channel %s: %w", bc.Name, err)
+			}
+			// Create replaces false by the column default
+			if err := tx.Model(&channel).Update("is_active", bc.IsActive).Error; err != nil {
+				return 0, fmt.Errorf("failed to update channel %s: %w", bc.Name, err)
+			}
+		} else if err != nil {
+			return 0, fmt.Errorf("failed to get channel %s: %w", bc.Name, err)
+		}
+
+		var appChannel models.ApplicationChannel
+		err := tx.Where("app_id = ? AND channel_name = ?", appID, bc.Name).First(&appChannel).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("failed to get application channel %s: %w", bc.Name, err)
+		}
+		exists := err == nil
+		if exists && !overwrite {
+			continue
+		}
+
+		appChannel.AppID = appID
+		appChannel.ChannelName = bc.Name
+		appChannel.IsEnabled = bc.IsEnabled
+		appChannel.AutoPublish = bc.AutoPublish
+		appChannel.RolloutPercentage = bc.RolloutPercentage
+		appChannel.CheckInterval = bc.CheckInterval
+		if err := tx.Save(&appChannel).Error; err != nil {
+			return 0, fmt.Errorf("failed to save application channel %s: %w", bc.Name, err)
+		}
+		// Saving a new record replaces false by the column default
+		if err := tx.Model(&appChannel).Update("is_enabled", bc.IsEnabled).Error; err != nil {
+			return 0, fmt.Errorf("failed to save application channel %s: %w", bc.Name, err)
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// importReleaseLines applies the bundled release lines
+func (s *BundleService) importReleaseLines(tx *gorm.DB, appID string, lines []models.ReleaseLineRequest, overwrite bool) (int, error) {
+	applied := 0
+	for _, req := range lines {
+		// Deleted lines are restored, they still hold their name
+		var line models.ReleaseLine
+		err := tx.Unscoped().Where("app_id = ? AND name = ?", appID, req.Name).First(&line).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("failed to get release line %s: %w", req.Name, err)
+		}
+		if err == nil && !line.DeletedAt.Valid && !overwrite {
+			continue
+		}
+
+		line.AppID = appID
+		line.Name = req.Name
+		line.DisplayName = req.DisplayName
+		line.Description = req.Description
+		line.VersionRange = req.VersionRange
+		line.ClientVersionRange = req.ClientVersionRange
+		line.Priority = req.Priority
+		line.IsActive = req.IsActive
+		line.DeletedAt = gorm.DeletedAt{}
+		if err := tx.Unscoped().Save(&line).Error; err != nil {
+			return 0, fmt.Errorf("failed to save release line %s: %w", req.Name, err)
+		}
+		// Saving a new record replaces false by the column default
+		if err := tx.Model(&line).Update("is_active", req.IsActive).Error; err != nil {
+			return 0, fmt.Errorf("failed to save release line %s: %w", req.Name, err)
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// importVersions creates the bundled versions; existing versions are skipped, or
+// replaced together with their artifacts and localizations when overwriting. Versions of
+// the application that the bundle doesn't contain are kept either way.
+func (s *BundleService) importVersions(tx *gorm.DB, appID string, versions []models.BundleVersion, overwrite bool, report *models.BundleImportReport) error {
+	for i := range versions {
+		bv := &versions[i]
+
+		version := newImportedVersion(appID, &bv.ImportVersion)
+		version.DeprecatedAt = bv.DeprecatedAt
+		version.EOLAt = bv.EOLAt
+		for _, l := range bv.Localizations {
+			version.Localizations = append(version.Localizations, models.VersionLocalization{
+				Locale:          l.Locale,
+				Title:           l.Title,
+				Description:     l.Description,
+				ReleaseNotes:    l.ReleaseNotes,
+				BreakingChanges: l.BreakingChanges,
+			})
+		}
+
+		var existing models.Version
+		err := tx.Where("app_id = ? AND version = ?", appID, bv.Version).First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get version %s: %w", bv.Version, err)
+		}
+
+		if err == nil {
+			if !overwrite {
+				report.VersionsSkipped++
+				continue
+			}
+
+			if err := tx.Unscoped().Where("version_id = ?", existing.ID).Delete(&models.VersionArtifact{}).Error; err != nil {
+				return fmt.Errorf("failed to replace artifacts of version %s: %w", bv.Version, err)
+			}
+			if err := tx.Unscoped().Where("version_id = ?", existing.ID).Delete(&models.VersionLocalization{}).Error; err != nil {
+				return fmt.Errorf("failed to replace localizations of version %s: %w", bv.Version, err)
+			}
+
+			version.ID = existing.ID
+			version.CreatedAt = existing.CreatedAt
+			if err := tx.Save(version).Error; err != nil {
+				return fmt.Errorf("failed to update version %s: %w", bv.Version, err)
+			}
+			report.VersionsUpdated++
+			continue
+		}
+
+		if err := tx.Create(version).Error; err != nil {
+			return fmt.Errorf("failed to create version %s: %w", bv.Version, err)
+		}
+		report.VersionsCreated++
+	}
+	return nil
+}
+
+// checkBundleFiles checks the files included in a bundle before anything is imported
+func checkBundleFiles(archive *zip.Reader) error {
+	for _, file := range archive.File {
+		if !strings.HasPrefix(file.Name, models.BundleFilesDir) || file.FileInfo().IsDir() {
+			continue
+		}
+		if !isUploadedFile("/uploads/" + strings.TrimPrefix(file.Name, models.BundleFilesDir)) {
+			return fmt.Errorf("invalid file path in bundle: %s", file.Name)
+		}
+		if file.UncompressedSize64 > uint64(maxBundleFileSize) {
+			return fmt.Errorf("file %s in bundle exceeds the maximum size of %d bytes", file.Name, maxBundleFileSize)
+		}
+	}
+	return nil
+}
+
+// restoreBundleFiles extracts the files included in a bundle into the upload directory.
+// Existing files are kept unless overwrite is set.
+func restoreBundleFiles(archive *zip.Reader, overwrite bool) (int, error) {
+	restored := 0
+	for _, file := range archive.File {
+		if !strings.HasPrefix(file.Name, models.BundleFilesDir) || file.FileInfo().IsDir() {
+			continue
+		}
+
+		fileURL := "/uploads/" + strings.TrimPrefix(file.Name, models.BundleFilesDir)
+		if !isUploadedFile(fileURL) {
+			return restored, fmt.Errorf("invalid file path in bundle: %s", file.Name)
+		}
+
+		target := uploadedFilePath(fileURL)
+		if _, err := os.Stat(target); err == nil && !overwrite {
+			continue
+		}
+
+		if err := extractBundleFile(file, target); err != nil {
+			return restored, err
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// extractBundleFile writes a file of the archive to the target path. Files larger than
+// maxBundleFileSize or than the size the archive declares for them are rejected, so that
+// crafted archives can't fill the disk.
+func extractBundleFile(file *zip.File, target string) error {
+	if file.UncompressedSize64 > uint64(maxBundleFileSize) {
+		return fmt.Errorf("file %s in bundle exceeds the maximum size of %d bytes", file.Name, maxBundleFileSize)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", file.Name, err)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s in bundle: %w", file.Name, err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", target, err)
+	}
+	defer dst.Close()
+
+	size := int64(file.UncompressedSize64)
+	written, err := io.Copy(dst, io.LimitReader(src, size+1))
+	if err == nil && written > size {
+		err = fmt.Errorf("more data than the declared %d bytes", size)
+	}
+	if err != nil {
+		dst.Close()
+		os.Remove(target)
+		return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// isUploadedFile checks if a file URL points to a file uploaded to this instance
+func isUploadedFile(fileURL string) bool {
+	if !strings.HasPrefix(fileURL, "/uploads/") {
+		return false
+	}
+	cleaned := path.Clean(fileURL)
+	return cleaned == fileURL && !strings.Contains(cleaned, "..")
+}
+
+// uploadedFilePath returns the local path of an uploaded file URL
+func uploadedFilePath(fileURL string) string {
+	return filepath.Join(".", filepath.FromSlash(fileURL))
+}
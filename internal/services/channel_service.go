@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/Run-Panel/VerTree/internal/database"
@@ -8,6 +9,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrChannelNotEnabled is returned, wrapped in a message naming the channel, when a channel
+// is not enabled for an application
+var ErrChannelNotEnabled = errors.New("not enabled for this application")
+
 // ChannelService handles channel-related business logic
 type ChannelService struct {
 	db *gorm.DB
@@ -197,6 +202,7 @@ func (s *ChannelService) EnableChannelForApp(appID, channelName string, req *mod
 			IsEnabled:         req.IsEnabled,
 			AutoPublish:       req.AutoPublish,
 			RolloutPercentage: req.RolloutPercentage,
+			CheckInterval:     req.CheckInterval,
 		}
 
 		if err := s.db.Create(&appChannel).Error; err != nil {
@@ -209,6 +215,7 @@ func (s *ChannelService) EnableChannelForApp(appID, channelName string, req *mod
 		appChannel.IsEnabled = req.IsEnabled
 		appChannel.AutoPublish = req.AutoPublish
 		appChannel.RolloutPercentage = req.RolloutPercentage
+		appChannel.CheckInterval = req.CheckInterval
 
 		if err := s.db.Save(&appChannel).Error; err != nil {
 			return nil, fmt.Errorf("failed to update application-channel relationship: %w", err)
@@ -340,7 +347,7 @@ func (s *ChannelService) ValidateChannelForApp(appID, channelName string) error
 	var appChannel models.ApplicationChannel
 	if err := s.db.Where("app_id = ? AND channel_name = ? AND is_enabled = ?", appID, channelName, true).First(&appChannel).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return fmt.Errorf("channel %s is not enabled for this application", channelName)
+			return fmt.Errorf("channel %s is %w", channelName, ErrChannelNotEnabled)
 		}
 		return fmt.Errorf("failed to validate channel: %w", err)
 	}
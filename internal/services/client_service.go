@@ -0,0 +1,210 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/database"
+	"github.com/Run-Panel/VerTree/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrClientNotFound is returned when a client is not in the inventory
+var ErrClientNotFound = errors.New("client not found")
+
+// ClientService maintains the inventory of client installations
+type ClientService struct {
+	db *gorm.DB
+}
+
+// NewClientService creates a new client service instance
+func NewClientService() *ClientService {
+	return &ClientService{
+		db: database.DB,
+	}
+}
+
+// RecordCheck updates the inventory from an update check of a client
+func (s *ClientService) RecordCheck(req *models.CheckUpdateRequest, clientIP string) error {
+	now := time.Now().UTC()
+	client := &models.Client{
+		AppID:          req.AppID,
+		ClientID:       req.ClientID,
+		CurrentVersion: req.CurrentVersion,
+		Channel:        req.Channel,
+		OS:             req.OS,
+		Arch:           req.Arch,
+		Region:         req.Region,
+		IPAddress:      clientIP,
+		FirstSeenAt:    now,
+		LastSeenAt:     now,
+		LastCheckAt:    &now,
+	}
+
+	return s.upsert(client, "current_version", "channel", "os", "arch", "region", "ip_address", "last_check_at")
+}
+
+// RecordInstall updates the inventory from an install result of a client. A successful
+// install makes the installed version the current version of the client.
+func (s *ClientService) RecordInstall(appID, clientID, version string, success bool, errorMessage string, at time.Time, clientIP string) error {
+	status := "success"
+	if !success {
+		status = "failed"
+	}
+
+	client := &models.Client{
+		AppID:              appID,
+		ClientID:           clientID,
+		IPAddress:          clientIP,
+		LastInstallVersion: version,
+		LastInstallStatus:  status,
+		LastInstallError:   errorMessage,
+		FirstSeenAt:        at,
+		LastSeenAt:         at,
+		LastInstallAt:      &at,
+	}
+
+	columns := []string{"ip_address", "last_install_version", "last_install_status", "last_install_error", "last_install_at"}
+	if success {
+		client.CurrentVersion = version
+		columns = append(columns, "current_version")
+	}
+
+	return s.upsert(client, columns...)
+}
+
+// RecordOffer records that an update to version was offered to a client and reports whether
+// it is the first offer of that version, so that clients polling without upgrading are
+// counted once. Clients not in the inventory yet are not recorded.
+func (s *ClientService) RecordOffer(appID, clientID, version string) (bool, error) {
+	result := s.db.Model(&models.Client{}).
+		Where("app_id = ? AND client_id = ?", appID, clientID).
+		Where("last_offered_version IS NULL OR last_offered_version != ?", version).
+		UpdateColumn("last_offered_version", version)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record offer: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// upsert creates the client or updates the given columns of the existing one. Empty values
+// don't overwrite known ones, and events older than the last one seen (e.g. replayed
+// telemetry) don't overwrite newer state. Times must be in UTC, SQLite compares them as text.
+func (s *ClientService) upsert(client *models.Client, columns ...string) error {
+	if client.AppID == "" || client.ClientID == "" {
+		return nil
+	}
+
+	values := map[string]string{
+		"current_version":      client.CurrentVersion,
+		"channel":              client.Channel,
+		"os":                   client.OS,
+		"arch":                 client.Arch,
+		"region":               client.Region,
+		"ip_address":           client.IPAddress,
+		"last_install_version": client.LastInstallVersion,
+		"last_install_status":  client.LastInstallStatus,
+	}
+
+	updated := []string{"last_seen_at", "updated_at"}
+	for _, column := range columns {
+		if value, ok := values[column]; ok && value == "" {
+			continue
+		}
+		updated = append(updated, column)
+	}
+
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "app_id"}, {Name: "client_id"}},
+		DoUpdates: clause.AssignmentColumns(updated),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "clients.last_seen_at <= excluded.last_seen_at"},
+		}},
+	}).Create(client).Error
+	if err != nil {
+		return fmt.Errorf("failed to record client: %w", err)
+	}
+
+	return nil
+}
+
+// ListClients retrieves the clients of an application with filters and pagination
+func (s *ClientService) ListClients(appID string, req *models.ClientListRequest) (*models.PaginatedResponse, error) {
+	var app models.Application
+	if err := s.db.Where("app_id = ?", appID).First(&app).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("application not found")
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	query := s.db.Model(&models.Client{}).Where("app_id = ?", appID)
+	if req.Search != "" {
+		query = query.Where("client_id LIKE ?", "%"+req.Search+"%")
+	}
+	if req.Version != "" {
+		query = query.Where("current_version = ?", req.Version)
+	}
+	if req.Channel != "" {
+		query = query.Where("channel = ?", req.Channel)
+	}
+	if req.OS != "" {
+		query = query.Where("os = ?", req.OS)
+	}
+	if req.Arch != "" {
+		query = query.Where("arch = ?", req.Arch)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count clients: %w", err)
+	}
+
+	var clients []models.Client
+	if err := query.Order("last_seen_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&clients).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch clients: %w", err)
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return &models.PaginatedResponse{
+		Code:    200,
+		Message: "success",
+		Data:    clients,
+		Pagination: models.PaginationResponse{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+// GetClient retrieves a client of an application by its client ID
+func (s *ClientService) GetClient(appID, clientID string) (*models.Client, error) {
+	var client models.Client
+	if err := s.db.Where("app_id = ? AND client_id = ?", appID, clientID).First(&client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrClientNotFound
+		}
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	return &client, nil
+}
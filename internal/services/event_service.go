@@ -0,0 +1,189 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+)
+
+const (
+	// releaseEventBufferSize is the number of recent events kept for Last-Event-ID replay
+	releaseEventBufferSize = 1000
+	// releaseEventQueueSize is the number of undelivered events a subscriber may lag behind
+	// before it is disconnected (it catches up through replay when it reconnects)
+	releaseEventQueueSize = 32
+)
+
+// defaultEventBroker is shared by all event service instances of the process
+var defaultEventBroker = newEventBroker(releaseEventBufferSize)
+
+// EventSubscription receives the release events of an app (and optionally a single channel).
+// Events is closed when the subscription ends.
+type EventSubscription struct {
+	AppID   string
+	Channel string
+	Events  chan *models.ReleaseEvent
+}
+
+// matches checks if an event is relevant for the subscription
+func (s *EventSubscription) matches(event *models.ReleaseEvent) bool {
+	return event.AppID == s.AppID && (s.Channel == "" || event.Channel == "" || event.Channel == s.Channel)
+}
+
+// eventBroker fans out release events to subscribers and keeps a ring buffer of recent
+// events. Event IDs start at the process start time in milliseconds so that IDs from a
+// previous process are always older than the buffer and trigger a resync.
+type eventBroker struct {
+	mu          sync.Mutex
+	baseID      uint64
+	nextID      uint64
+	buffer      []*models.ReleaseEvent
+	start       int
+	subscribers map[*EventSubscription]struct{}
+}
+
+// newEventBroker creates an event broker with a replay buffer of the given size
+func newEventBroker(size int) *eventBroker {
+	baseID := uint64(time.Now().UnixMilli())
+	return &eventBroker{
+		baseID:      baseID,
+		nextID:      baseID,
+		buffer:      make([]*models.ReleaseEvent, 0, size),
+		subscribers: make(map[*EventSubscription]struct{}),
+	}
+}
+
+// publish assigns an ID to an event, buffers it and delivers it to matching subscribers
+func (b *eventBroker) publish(event *models.ReleaseEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	event.ID = b.nextID
+	b.nextID++
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	if len(b.buffer) < cap(b.buffer) {
+		b.buffer = append(b.buffer, event)
+	} else {
+		b.buffer[b.start] = event
+		b.start = (b.start + 1) % len(b.buffer)
+	}
+
+	for sub := range b.subscribers {
+		if !sub.matches(event) {
+			continue
+		}
+		select {
+		case sub.Events <- event:
+		default:
+			// Too slow: drop the subscriber, it replays from its last event ID on reconnect
+			delete(b.subscribers, sub)
+			close(sub.Events)
+		}
+	}
+}
+
+// subscribe registers a subscription and returns the buffered events after lastEventID.
+// complete is false if events after lastEventID may have been missed: they were dropped
+// from the buffer or lastEventID was issued by a previous process. latestID is the ID of
+// the newest event so far.
+func (b *eventBroker) subscribe(sub *EventSubscription, lastEventID uint64) (replay []*models.ReleaseEvent, complete bool, latestID uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers[sub] = struct{}{}
+	latestID = b.nextID - 1
+	if lastEventID == 0 {
+		return nil, true, latestID
+	}
+
+	oldest := b.nextID
+	if len(b.buffer) > 0 {
+		oldest = b.buffer[b.start].ID
+	}
+	complete = lastEventID >= b.baseID-1 && lastEventID <= latestID && lastEventID+1 >= oldest
+
+	for i := 0; i < len(b.buffer); i++ {
+		event := b.buffer[(b.start+i)%len(b.buffer)]
+		if event.ID > lastEventID && sub.matches(event) {
+			replay = append(replay, event)
+		}
+	}
+	return replay, complete, latestID
+}
+
+// unsubscribe removes a subscription and closes its channel
+func (b *eventBroker) unsubscribe(sub *EventSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.Events)
+	}
+}
+
+// closeAll ends all subscriptions
+func (b *eventBroker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.Events)
+	}
+}
+
+// EventService publishes release events and manages client subscriptions
+type EventService struct {
+	broker *eventBroker
+}
+
+// NewEventService creates a new event service instance
+func NewEventService() *EventService {
+	return &EventService{
+		broker: defaultEventBroker,
+	}
+}
+
+// Publish sends a release event to the subscribed clients of its app and channel
+func (s *EventService) Publish(event *models.ReleaseEvent) {
+	s.broker.publish(event)
+}
+
+// Subscribe subscribes to the release events of an app, optionally limited to a channel.
+// Events after lastEventID that are still buffered are returned for replay; if some may
+// have been missed a single resync event is returned instead.
+func (s *EventService) Subscribe(appID, channel string, lastEventID uint64) (*EventSubscription, []*models.ReleaseEvent) {
+	sub := &EventSubscription{
+		AppID:   appID,
+		Channel: channel,
+		Events:  make(chan *models.ReleaseEvent, releaseEventQueueSize),
+	}
+
+	replay, complete, latestID := s.broker.subscribe(sub, lastEventID)
+	if !complete {
+		// The resync event carries the latest ID so the client's position becomes current
+		replay = []*models.ReleaseEvent{{
+			ID:        latestID,
+			Type:      models.ReleaseEventResync,
+			AppID:     appID,
+			Channel:   channel,
+			CreatedAt: time.Now(),
+		}}
+	}
+	return sub, replay
+}
+
+// Unsubscribe ends a subscription
+func (s *EventService) Unsubscribe(sub *EventSubscription) {
+	s.broker.unsubscribe(sub)
+}
+
+// CloseReleaseEventStreams ends all event subscriptions, e.g. on server shutdown
+func CloseReleaseEventStreams() {
+	defaultEventBroker.closeAll()
+}
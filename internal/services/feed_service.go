@@ -0,0 +1,292 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/database"
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/utils"
+	"gorm.io/gorm"
+)
+
+// FeedService builds update feeds for third-party updater frameworks
+type FeedService struct {
+	db         *gorm.DB
+	channelSvc *ChannelService
+	versionCmp *utils.VersionComparer
+}
+
+// NewFeedService creates a new feed service instance
+func NewFeedService() *FeedService {
+	return &FeedService{
+		db:         database.DB,
+		channelSvc: NewChannelService(),
+		versionCmp: utils.NewVersionComparer(),
+	}
+}
+
+// FeedLinks describes how URLs in a feed are built: baseURL is the scheme and host of
+// the server and query is appended to feed links, e.g. to pass the feed token along
+type FeedLinks struct {
+	BaseURL string
+	Query   url.Values
+}
+
+// absolute turns a server-relative path into an absolute URL
+func (l FeedLinks) absolute(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return strings.TrimSuffix(l.BaseURL, "/") + path
+}
+
+// link builds an absolute feed link with the feed query and extra parameters
+func (l FeedLinks) link(path string, extra url.Values) string {
+	query := url.Values{}
+	for key, values := range l.Query {
+		query[key] = values
+	}
+	for key, values := range extra {
+		query[key] = values
+	}
+
+	link := l.absolute(path)
+	if encoded := query.Encode(); encoded != "" {
+		link += "?" + encoded
+	}
+	return link
+}
+
+// getFeedVersions returns the published versions of an app and channel, highest version
+// first, with their artifacts and localizations
+func (s *FeedService) getFeedVersions(appID, channel string) ([]*models.Version, error) {
+	if err := s.channelSvc.ValidateChannelForApp(appID, channel); err != nil {
+		return nil, err
+	}
+
+	var versions []*models.Version
+	if err := s.db.Preload("Artifacts").Preload("Localizations").
+		Where("app_id = ? AND channel = ? AND is_published = ?", appID, channel, true).
+		Order("publish_time DESC").
+		Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get published versions: %w", err)
+	}
+
+	// Versions aren't published in version order, an LTS hotfix may follow a newer major
+	sort.SliceStable(versions, func(i, j int) bool {
+		return s.versionCmp.CompareVersions(versions[i].Version, versions[j].Version) > 0
+	})
+
+	return versions, nil
+}
+
+// BuildAppcast builds the Sparkle appcast of an application channel from its published versions.
+// The enclosure is the macOS artifact of a version (preferring universal builds) with its
+// EdDSA signature, falling back to the version's default download.
+func (s *FeedService) BuildAppcast(app *models.Application, channel string, links FeedLinks) (*models.Appcast, error) {
+	versions, err := s.getFeedVersions(app.AppID, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	appcast := &models.Appcast{
+		Version:      "2.0",
+		XMLNSSparkle: models.SparkleNamespace,
+		XMLNSDC:      models.DublinCoreNamespace,
+		Channel: models.AppcastChannel{
+			Title:       fmt.Sprintf("%s (%s)", app.Name, channel),
+			Link:        links.link(fmt.Sprintf("/feeds/%s/%s/appcast.xml", app.AppID, channel), nil),
+			Description: app.Description,
+			Items:       []models.AppcastItem{},
+		},
+	}
+
+	for _, version := range versions {
+		shortVersion := strings.TrimPrefix(version.Version, "v")
+		item := models.AppcastItem{
+			Title:                version.Title,
+			Version:              shortVersion,
+			ShortVersionString:   shortVersion,
+			MinimumSystemVersion: version.MinOSVersion,
+			MaximumSystemVersion: version.MaxOSVersion,
+			Enclosure: models.AppcastEnclosure{
+				URL:    links.absolute(version.FileURL),
+				Length: version.FileSize,
+				Type:   models.AppcastEnclosureType,
+			},
+		}
+		if version.PublishTime != nil {
+			item.PubDate = version.PublishTime.UTC().Format(time.RFC1123Z)
+		}
+		if version.IsForced {
+			item.CriticalUpdate = &struct{}{}
+		}
+
+		if artifact := macArtifact(version); artifact != nil {
+			item.Enclosure.URL = links.absolute(artifact.FileURL)
+			item.Enclosure.Length = artifact.FileSize
+			item.Enclosure.EdSignature = artifact.Signature
+		}
+
+		notesPath := fmt.Sprintf("/feeds/%s/release-notes/%s", app.AppID, url.PathEscape(version.Version))
+		item.ReleaseNotesLinks = append(item.ReleaseNotesLinks, models.AppcastReleaseNotesLink{
+			URL: links.link(notesPath, nil),
+		})
+		for _, localization := range version.Localizations {
+			item.ReleaseNotesLinks = append(item.ReleaseNotesLinks, models.AppcastReleaseNotesLink{
+				Lang: localization.Locale,
+				URL:  links.link(notesPath, url.Values{"lang": {localization.Locale}}),
+			})
+		}
+
+		appcast.Channel.Items = append(appcast.Channel.Items, item)
+	}
+
+	return appcast, nil
+}
+
+// macArtifact returns the macOS artifact of a version, preferring universal builds
+func macArtifact(version *models.Version) *models.VersionArtifact {
+	if artifact := version.FindArtifact("darwin", "universal"); artifact != nil {
+		return artifact
+	}
+	for i := range version.Artifacts {
+		if strings.EqualFold(version.Artifacts[i].OS, "darwin") {
+			return &version.Artifacts[i]
+		}
+	}
+	return nil
+}
+
+// rolloutPercentage returns the rollout percentage of an app channel (100 if not configured)
+func (s *FeedService) rolloutPercentage(appID, channel string) int {
+	var appChannel models.ApplicationChannel
+	if err := s.db.Where("app_id = ? AND channel_name = ?", appID, channel).First(&appChannel).Error; err != nil {
+		return 100
+	}
+	return appChannel.RolloutPercentage
+}
+
+// ParseElectronManifestName parses the name of an electron-updater manifest, e.g.
+// "latest.yml" (Windows), "beta-mac.yml" or "latest-linux-arm64.yml", into the
+// channel, OS and architecture it describes. electron-updater's default "latest"
+// channel maps to the stable channel.
+func ParseElectronManifestName(name string) (channel, os, arch string, err error) {
+	if !strings.HasSuffix(name, ".yml") {
+		return "", "", "", fmt.Errorf("invalid manifest name: %s", name)
+	}
+	channel = strings.TrimSuffix(name, ".yml")
+
+	os = "windows"
+	switch {
+	case strings.HasSuffix(channel, "-mac"):
+		channel, os = strings.TrimSuffix(channel, "-mac"), "darwin"
+	case strings.HasSuffix(channel, "-linux-arm64"):
+		channel, os, arch = strings.TrimSuffix(channel, "-linux-arm64"), "linux", "arm64"
+	case strings.HasSuffix(channel, "-linux"):
+		channel, os, arch = strings.TrimSuffix(channel, "-linux"), "linux", "amd64"
+	}
+
+	if channel == "" {
+		return "", "", "", fmt.Errorf("invalid manifest name: %s", name)
+	}
+	if channel == "latest" {
+		channel = "stable"
+	}
+	return channel, os, arch, nil
+}
+
+// electronFiles returns the artifacts of a version usable by electron-updater on a
+// platform. Linux manifests are per architecture; Windows and macOS manifests list
+// every build and let the updater pick one.
+func electronFiles(version *models.Version, os, arch string) []models.VersionArtifact {
+	var files []models.VersionArtifact
+	for _, artifact := range version.Artifacts {
+		if !strings.EqualFold(artifact.OS, os) || artifact.SHA512 == "" {
+			continue
+		}
+		if arch != "" && artifact.Arch != "" && !strings.EqualFold(artifact.Arch, arch) {
+			continue
+		}
+		files = append(files, artifact)
+	}
+	return files
+}
+
+// BuildElectronManifest builds the electron-updater manifest of an application channel and
+// platform from the highest published version that has artifacts with SHA512 hashes for it.
+// The channel's rollout percentage is passed on as stagingPercentage.
+func (s *FeedService) BuildElectronManifest(appID, channel, os, arch string, links FeedLinks) (*models.ElectronUpdateInfo, error) {
+	versions, err := s.getFeedVersions(appID, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, version := range versions {
+		files := electronFiles(version, os, arch)
+		if len(files) == 0 {
+			continue
+		}
+
+		info := &models.ElectronUpdateInfo{
+			Version:      strings.TrimPrefix(version.Version, "v"),
+			Path:         links.absolute(files[0].FileURL),
+			SHA512:       files[0].SHA512,
+			ReleaseName:  version.Title,
+			ReleaseNotes: version.ReleaseNotes,
+		}
+		if version.PublishTime != nil {
+			info.ReleaseDate = version.PublishTime.UTC().Format(time.RFC3339)
+		}
+		if percentage := s.rolloutPercentage(appID, channel); percentage < 100 {
+			info.StagingPercentage = percentage
+		}
+		for _, file := range files {
+			info.Files = append(info.Files, models.ElectronFileInfo{
+				URL:    links.absolute(file.FileURL),
+				SHA512: file.SHA512,
+				Size:   file.FileSize,
+			})
+		}
+		return info, nil
+	}
+
+	return nil, fmt.Errorf("no published version with %s artifacts in channel %s", os, channel)
+}
+
+// BuildSquirrelReleases builds the Squirrel.Windows RELEASES file of an application channel
+// from the Windows .nupkg artifacts of its published versions, lowest first. Squirrel doesn't
+// identify clients, so the channel's rollout percentage is applied by hashing clientKey
+// (e.g. the client IP); clients outside the rollout don't see the highest version.
+func (s *FeedService) BuildSquirrelReleases(appID, channel, arch, clientKey string, links FeedLinks) (string, error) {
+	versions, err := s.getFeedVersions(appID, channel)
+	if err != nil {
+		return "", err
+	}
+
+	if percentage := s.rolloutPercentage(appID, channel); percentage < 100 && len(versions) > 0 {
+		if rolloutHash(clientKey)%100 >= percentage {
+			versions = versions[1:]
+		}
+	}
+
+	var lines []string
+	for i := len(versions) - 1; i >= 0; i-- {
+		for _, artifact := range versions[i].Artifacts {
+			if !strings.EqualFold(artifact.OS, "windows") || artifact.SHA1 == "" ||
+				!strings.HasSuffix(strings.ToLower(artifact.FileURL), ".nupkg") {
+				continue
+			}
+			if arch != "" && artifact.Arch != "" && !strings.EqualFold(artifact.Arch, arch) {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("%s %s %d", strings.ToUpper(artifact.SHA1), links.absolute(artifact.FileURL), artifact.FileSize))
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
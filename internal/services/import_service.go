@@ -0,0 +1,417 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/database"
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/utils"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// ImportService handles bulk import of historical versions from release manifests
+type ImportService struct {
+	db             *gorm.DB
+	versionSvc     *VersionService
+	channelService *ChannelService
+	versionCmp     *utils.VersionComparer
+}
+
+// NewImportService creates a new import service instance
+func NewImportService() *ImportService {
+	return &ImportService{
+		db:             database.DB,
+		versionSvc:     NewVersionService(),
+		channelService: NewChannelService(),
+		versionCmp:     utils.NewVersionComparer(),
+	}
+}
+
+// githubRelease represents a release of the GitHub Releases API
+type githubRelease struct {
+	TagName     string        `json:"tag_name"`
+	Name        string        `json:"name"`
+	Body        string        `json:"body"`
+	Draft       bool          `json:"draft"`
+	Prerelease  bool          `json:"prerelease"`
+	PublishedAt *time.Time    `json:"published_at"`
+	Assets      []githubAsset `json:"assets"`
+}
+
+// githubAsset represents a release asset of the GitHub Releases API
+type githubAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+	Size               int64  `json:"size"`
+	Digest             string `json:"digest"` // e.g. "sha256:<hex>"
+}
+
+// metadataExtensions lists release assets that describe other assets rather than being downloads
+var metadataExtensions = []string{".sig", ".asc", ".sha256", ".sha512", ".txt", ".yml", ".yaml", ".blockmap", ".json"}
+
+// ParseManifest parses an import manifest in the given format. An empty format is
+// detected from the content: a JSON array is treated as a GitHub Releases export,
+// a JSON object as a manifest and anything else as YAML.
+func (s *ImportService) ParseManifest(data []byte, format, defaultChannel string) (*models.ImportManifest, error) {
+	if format == "" {
+		trimmed := bytes.TrimSpace(data)
+		switch {
+		case bytes.HasPrefix(trimmed, []byte("[")):
+			format = models.ImportFormatGitHub
+		case bytes.HasPrefix(trimmed, []byte("{")):
+			format = models.ImportFormatJSON
+		default:
+			format = models.ImportFormatYAML
+		}
+	}
+
+	format = strings.ToLower(format)
+	if format == "yml" {
+		format = models.ImportFormatYAML
+	}
+
+	var manifest models.ImportManifest
+	switch format {
+	case models.ImportFormatJSON:
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON manifest: %w", err)
+		}
+	case models.ImportFormatYAML:
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML manifest: %w", err)
+		}
+	case models.ImportFormatGitHub:
+		var releases []githubRelease
+		if err := json.Unmarshal(data, &releases); err != nil {
+			return nil, fmt.Errorf("failed to parse GitHub releases export: %w", err)
+		}
+		manifest.Versions = convertGitHubReleases(releases)
+	default:
+		return nil, fmt.Errorf("unsupported manifest format: %s", format)
+	}
+
+	// The default channel only applies to manifests that don't declare their own
+	if manifest.Channel == "" {
+		manifest.Channel = defaultChannel
+	}
+	if manifest.Channel == "" {
+		manifest.Channel = "stable"
+	}
+	manifest.Format = format
+
+	return &manifest, nil
+}
+
+// convertGitHubReleases converts GitHub releases into manifest versions. Drafts are
+// skipped and prereleases go to the beta channel. The first downloadable asset becomes
+// the default download, and assets with a recognizable platform become artifacts.
+func convertGitHubReleases(releases []githubRelease) []models.ImportVersion {
+	versions := make([]models.ImportVersion, 0, len(releases))
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+
+		version := models.ImportVersion{
+			Version:      release.TagName,
+			Title:        release.Name,
+			ReleaseNotes: release.Body,
+			IsPublished:  true,
+			PublishTime:  release.PublishedAt,
+		}
+		if release.Prerelease {
+			version.Channel = "beta"
+		}
+
+		seen := make(map[string]bool)
+		for _, asset := range release.Assets {
+			if isMetadataAsset(asset.Name) {
+				continue
+			}
+
+			if version.FileURL == "" {
+				version.FileURL = asset.BrowserDownloadURL
+				version.FileSize = asset.Size
+				version.FileChecksum = asset.Digest
+			}
+
+			os, arch := utils.DetectPlatform(asset.Name)
+			if os == "" || seen[os+"/"+arch] {
+				continue
+			}
+			seen[os+"/"+arch] = true
+
+			version.Artifacts = append(version.Artifacts, models.VersionArtifactRequest{
+				OS:           os,
+				Arch:         arch,
+				FileName:     asset.Name,
+				FileURL:      asset.BrowserDownloadURL,
+				FileSize:     asset.Size,
+				FileChecksum: asset.Digest,
+			})
+		}
+
+		versions = append(versions, version)
+	}
+	return versions
+}
+
+// isMetadataAsset checks if a release asset is a signature, checksum or metadata file
+func isMetadataAsset(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, metadataExt := range metadataExtensions {
+		if ext == metadataExt {
+			return true
+		}
+	}
+	return false
+}
+
+// Import validates a manifest and creates its versions and artifacts for an application
+// in a single transaction. Nothing is written if the manifest is invalid or on a dry run;
+// the returned report lists the validation problems and the planned actions.
+func (s *ImportService) Import(appID string, manifest *models.ImportManifest, opts models.ImportOptions) (*models.ImportReport, error) {
+	var app models.Application
+	if err := s.db.Where("app_id = ?", appID).First(&app).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("application not found")
+		}
+		return nil, fmt.Errorf("failed to find application: %w", err)
+	}
+
+	report := &models.ImportReport{
+		AppID:    appID,
+		DryRun:   opts.DryRun,
+		Total:    len(manifest.Versions),
+		Versions: []models.ImportVersionResult{},
+		Errors:   []models.ImportIssue{},
+		Warnings: []models.ImportIssue{},
+	}
+
+	var toCreate []*models.Version
+	seen := make(map[string]bool)
+	for i := range manifest.Versions {
+		entry := &manifest.Versions[i]
+		if entry.Channel == "" {
+			entry.Channel = manifest.Channel
+		}
+		if entry.Title == "" {
+			entry.Title = entry.Version
+		}
+
+		problems, warnings := s.validateEntry(appID, entry, manifest.Format != models.ImportFormatGitHub)
+		if entry.Version != "" {
+			if seen[entry.Version] {
+				problems = append(problems, "duplicate version in manifest")
+			}
+			seen[entry.Version] = true
+		}
+
+		action := models.ImportActionCreate
+		var existing models.Version
+		if err := s.db.Where("app_id = ? AND version = ?", appID, entry.Version).First(&existing).Error; err == nil {
+			if opts.SkipExisting {
+				action = models.ImportActionSkip
+				problems, warnings = nil, nil
+			} else {
+				problems = append(problems, "version already exists for this app")
+			}
+		}
+
+		for _, problem := range problems {
+			report.Errors = append(report.Errors, models.ImportIssue{
+				Index:   i,
+				Version: entry.Version,
+				Message: problem,
+			})
+		}
+		for _, warning := range warnings {
+			report.Warnings = append(report.Warnings, models.ImportIssue{
+				Index:   i,
+				Version: entry.Version,
+				Message: warning,
+			})
+		}
+
+		report.Versions = append(report.Versions, models.ImportVersionResult{
+			Version:   entry.Version,
+			Channel:   entry.Channel,
+			Action:    action,
+			Artifacts: len(entry.Artifacts),
+		})
+
+		if action == models.ImportActionSkip {
+			report.Skipped++
+			continue
+		}
+		toCreate = append(toCreate, newImportedVersion(appID, entry))
+	}
+
+	if !report.Valid() || opts.DryRun {
+		return report, nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, version := range toCreate {
+			if err := tx.Create(version).Error; err != nil {
+				return fmt.Errorf("failed to create version %s: %w", version.Version, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill in the IDs of the created versions
+	created := make(map[string]uint, len(toCreate))
+	for _, version := range toCreate {
+		created[version.Version] = version.ID
+	}
+	for i := range report.Versions {
+		if id, ok := created[report.Versions[i].Version]; ok {
+			report.Versions[i].VersionID = id
+		}
+	}
+	report.Created = len(toCreate)
+
+	for _, version := range toCreate {
+		if version.IsPublished {
+			s.versionSvc.refreshTUFMetadata(appID)
+			break
+		}
+	}
+
+	return report, nil
+}
+
+// validateEntry validates a manifest version and returns the problems found. Missing
+// checksums are problems if requireChecksum is set and warnings otherwise.
+func (s *ImportService) validateEntry(appID string, entry *models.ImportVersion, requireChecksum bool) (problems, warnings []string) {
+	checkChecksum := func(prefix, checksum string) {
+		if checksum != "" {
+			return
+		}
+		if requireChecksum {
+			problems = append(problems, prefix+"file_checksum is required")
+		} else {
+			warnings = append(warnings, prefix+"no file_checksum, clients can't verify the download")
+		}
+	}
+
+	if entry.Version == "" {
+		problems = append(problems, "version is required")
+	} else if !s.versionCmp.IsValidSemVer(entry.Version) {
+		problems = append(problems, fmt.Sprintf("invalid semantic version: %s", entry.Version))
+	}
+
+	if err := s.channelService.ValidateChannelForApp(appID, entry.Channel); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if problem := validateImportFile(entry.FileURL, entry.FileSize); problem != "" {
+		problems = append(problems, problem)
+	}
+	checkChecksum("", entry.FileChecksum)
+
+	if entry.MinUpgradeVersion != "" && !s.versionCmp.IsValidSemVer(entry.MinUpgradeVersion) {
+		problems = append(problems, fmt.Sprintf("invalid min_upgrade_version: %s", entry.MinUpgradeVersion))
+	}
+
+	if err := s.versionSvc.validateConstraints(&models.VersionRequest{
+		MinOSVersion:         entry.MinOSVersion,
+		MaxOSVersion:         entry.MaxOSVersion,
+		RequiredDependencies: entry.RequiredDependencies,
+	}); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	platforms := make(map[string]bool)
+	for _, artifact := range entry.Artifacts {
+		if artifact.OS == "" {
+			problems = append(problems, fmt.Sprintf("artifact %s: os is required", artifact.FileURL))
+			continue
+		}
+		platform := artifact.OS + "/" + artifact.Arch
+		if platforms[platform] {
+			problems = append(problems, fmt.Sprintf("artifact %s: duplicate platform", platform))
+		}
+		platforms[platform] = true
+
+		if problem := validateImportFile(artifact.FileURL, artifact.FileSize); problem != "" {
+			problems = append(problems, fmt.Sprintf("artifact %s: %s", platform, problem))
+		}
+		checkChecksum(fmt.Sprintf("artifact %s: ", platform), artifact.FileChecksum)
+	}
+
+	return problems, warnings
+}
+
+// validateImportFile validates the download information of a version or artifact
+func validateImportFile(fileURL string, fileSize int64) string {
+	parsed, err := url.Parse(fileURL)
+	if fileURL == "" || err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Sprintf("invalid file_url: %q", fileURL)
+	}
+	if fileSize <= 0 {
+		return "file_size must be greater than 0"
+	}
+	return ""
+}
+
+// newImportedVersion builds the version model of a manifest entry
+func newImportedVersion(appID string, entry *models.ImportVersion) *models.Version {
+	version := &models.Version{
+		AppID:             appID,
+		Version:           entry.Version,
+		Channel:           entry.Channel,
+		Title:             entry.Title,
+		Description:       entry.Description,
+		ReleaseNotes:      entry.ReleaseNotes,
+		BreakingChanges:   entry.BreakingChanges,
+		MinUpgradeVersion: entry.MinUpgradeVersion,
+		FileURL:           entry.FileURL,
+		FileSize:          entry.FileSize,
+		FileChecksum:      entry.FileChecksum,
+		IsForced:          entry.IsForced,
+		IsPublished:       entry.IsPublished,
+
+		MinOSVersion:         entry.MinOSVersion,
+		MaxOSVersion:         entry.MaxOSVersion,
+		RequiredDependencies: entry.RequiredDependencies,
+		RequiredCapabilities: entry.RequiredCapabilities,
+	}
+
+	// Keep the historical publish time; fall back to now for published versions without one
+	if entry.IsPublished {
+		publishTime := time.Now()
+		if entry.PublishTime != nil {
+			publishTime = *entry.PublishTime
+		}
+		version.PublishTime = &publishTime
+	}
+
+	for _, artifact := range entry.Artifacts {
+		version.Artifacts = append(version.Artifacts, models.VersionArtifact{
+			OS:           artifact.OS,
+			Arch:         artifact.Arch,
+			FileName:     artifact.FileName,
+			FileURL:      artifact.FileURL,
+			FileSize:     artifact.FileSize,
+			FileChecksum: artifact.FileChecksum,
+			Signature:    artifact.Signature,
+			SHA512:       artifact.SHA512,
+			SHA1:         artifact.SHA1,
+		})
+	}
+
+	return version
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"gorm.io/gorm"
+)
+
+// maxLoadFactor limits how much the polling interval is stretched under load
+const maxLoadFactor = 10
+
+var (
+	// defaultCheckInterval is the polling interval of app channels without one, see SetPollingConfig
+	defaultCheckInterval = time.Hour
+
+	// checkLoad counts the update checks of all applications
+	checkLoad = &loadMonitor{capacity: 6000}
+)
+
+// SetPollingConfig sets the default interval between update checks and the number of update
+// checks per minute the server handles before it stretches the interval of clients
+func SetPollingConfig(interval time.Duration, capacity int) {
+	if interval > 0 {
+		defaultCheckInterval = interval
+	}
+	checkLoad.mutex.Lock()
+	checkLoad.capacity = capacity
+	checkLoad.mutex.Unlock()
+}
+
+// loadMonitor counts events per minute to measure the load of the server
+type loadMonitor struct {
+	mutex    sync.Mutex
+	capacity int // Events per minute at full load, 0 disables load shedding
+	minute   int64
+	current  int // Events in the current minute
+	previous int // Events in the previous minute
+}
+
+// record counts an event
+func (m *loadMonitor) record() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.advance(time.Now())
+	m.current++
+}
+
+// factor returns how many times the recent event rate exceeds the capacity, at least 1
+// and at most maxLoadFactor. The busier of the previous and the current minute counts,
+// so that the factor rises as soon as a spike starts and doesn't drop at every new minute.
+func (m *loadMonitor) factor() float64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.capacity <= 0 {
+		return 1
+	}
+
+	m.advance(time.Now())
+	rate := m.current
+	if m.previous > rate {
+		rate = m.previous
+	}
+
+	factor := float64(rate) / float64(m.capacity)
+	switch {
+	case factor < 1:
+		return 1
+	case factor > maxLoadFactor:
+		return maxLoadFactor
+	}
+	return factor
+}
+
+// advance moves the counters to the minute of now
+func (m *loadMonitor) advance(now time.Time) {
+	minute := now.Unix() / 60
+	switch {
+	case minute == m.minute:
+		return
+	case minute == m.minute+1:
+		m.previous = m.current
+	default:
+		m.previous = 0
+	}
+	m.current = 0
+	m.minute = minute
+}
+
+// nextCheckAfter returns the number of seconds the client should wait before checking for
+// updates again: the check interval of the app channel, or the default, stretched by the
+// current load. A random extra of up to 10% spreads clients that checked at the same time.
+func nextCheckAfter(db *gorm.DB, appID, channel string) int {
+	interval := defaultCheckInterval
+
+	var appChannel models.ApplicationChannel
+	if err := db.Select("check_interval").
+		Where("app_id = ? AND channel_name = ?", appID, channel).
+		First(&appChannel).Error; err == nil && appChannel.CheckInterval > 0 {
+		interval = time.Duration(appChannel.CheckInterval) * time.Second
+	}
+
+	seconds := interval.Seconds() * checkLoad.factor()
+	seconds += seconds * 0.1 * rand.Float64()
+	return int(seconds)
+}
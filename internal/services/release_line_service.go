@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Run-Panel/VerTree/internal/database"
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/utils"
+	"gorm.io/gorm"
+)
+
+// Errors returned, wrapped in a message naming the release line, when a release line can't
+// be used
+var (
+	ErrReleaseLineNotFound = errors.New("not found")
+	ErrReleaseLineInactive = errors.New("not active")
+)
+
+// ReleaseLineService handles release line business logic
+type ReleaseLineService struct {
+	db         *gorm.DB
+	versionCmp *utils.VersionComparer
+}
+
+// NewReleaseLineService creates a new release line service instance
+func NewReleaseLineService() *ReleaseLineService {
+	return &ReleaseLineService{
+		db:         database.DB,
+		versionCmp: utils.NewVersionComparer(),
+	}
+}
+
+// GetReleaseLines lists the release lines of an application
+func (s *ReleaseLineService) GetReleaseLines(appID string) ([]*models.ReleaseLine, error) {
+	var lines []*models.ReleaseLine
+	if err := s.db.Where("app_id = ?", appID).Order("priority DESC, name").Find(&lines).Error; err != nil {
+		return nil, fmt.Errorf("failed to get release lines: %w", err)
+	}
+	return lines, nil
+}
+
+// GetReleaseLine retrieves a release line of an application by name
+func (s *ReleaseLineService) GetReleaseLine(appID, name string) (*models.ReleaseLine, error) {
+	var line models.ReleaseLine
+	if err := s.db.Where("app_id = ? AND name = ?", appID, name).First(&line).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("release line %s %w", name, ErrReleaseLineNotFound)
+		}
+		return nil, fmt.Errorf("failed to get release line: %w", err)
+	}
+	return &line, nil
+}
+
+// CreateReleaseLine creates a new release line for an application
+func (s *ReleaseLineService) CreateReleaseLine(appID string, req *models.ReleaseLineRequest) (*models.ReleaseLine, error) {
+	var app models.Application
+	if err := s.db.Where("app_id = ?", appID).First(&app).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("application not found")
+		}
+		return nil, fmt.Errorf("failed to find application: %w", err)
+	}
+
+	if err := s.validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Include soft-deleted rows so the name of a deleted line can be used again, the
+	// unique index of application and name also covers them
+	line := &models.ReleaseLine{}
+	err := s.db.Unscoped().Where("app_id = ? AND name = ?", appID, req.Name).First(line).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check release line: %w", err)
+	}
+	if err == nil && !line.DeletedAt.Valid {
+		return nil, fmt.Errorf("release line %s already exists for this application", req.Name)
+	}
+
+	line.AppID = appID
+	line.Name = req.Name
+	line.DisplayName = req.DisplayName
+	line.Description = req.Description
+	line.VersionRange = req.VersionRange
+	line.ClientVersionRange = req.ClientVersionRange
+	line.Priority = req.Priority
+	line.IsActive = req.IsActive
+	line.DeletedAt = gorm.DeletedAt{}
+
+	if err := s.db.Unscoped().Save(line).Error; err != nil {
+		return nil, fmt.Errorf("failed to create release line: %w", err)
+	}
+	// Creating replaces false by the column default
+	if err := s.db.Model(line).Update("is_active", req.IsActive).Error; err != nil {
+		return nil, fmt.Errorf("failed to create release line: %w", err)
+	}
+
+	return line, nil
+}
+
+// UpdateReleaseLine updates an existing release line
+func (s *ReleaseLineService) UpdateReleaseLine(appID, name string, req *models.ReleaseLineRequest) (*models.ReleaseLine, error) {
+	line, err := s.GetReleaseLine(appID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Check if new name conflicts (if changed)
+	if req.Name != line.Name {
+		var existing models.ReleaseLine
+		if err := s.db.Where("app_id = ? AND name = ? AND id != ?", appID, req.Name, line.ID).First(&existing).Error; err == nil {
+			return nil, fmt.Errorf("release line %s already exists for this application", req.Name)
+		}
+
+		// A deleted line of the new name would still conflict on the unique index
+		if err := s.db.Unscoped().
+			Where("app_id = ? AND name = ? AND deleted_at IS NOT NULL", appID, req.Name).
+			Delete(&models.ReleaseLine{}).Error; err != nil {
+			return nil, fmt.Errorf("failed to update release line: %w", err)
+		}
+	}
+
+	line.Name = req.Name
+	line.DisplayName = req.DisplayName
+	line.Description = req.Description
+	line.VersionRange = req.VersionRange
+	line.ClientVersionRange = req.ClientVersionRange
+	line.Priority = req.Priority
+	line.IsActive = req.IsActive
+
+	if err := s.db.Save(line).Error; err != nil {
+		return nil, fmt.Errorf("failed to update release line: %w", err)
+	}
+
+	return line, nil
+}
+
+// DeleteReleaseLine deletes a release line
+func (s *ReleaseLineService) DeleteReleaseLine(appID, name string) error {
+	line, err := s.GetReleaseLine(appID, name)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Delete(line).Error; err != nil {
+		return fmt.Errorf("failed to delete release line: %w", err)
+	}
+
+	return nil
+}
+
+// ResolveForClient determines the release line a client is pinned to. An explicitly
+// requested line must exist and be active; otherwise the active server-side rules are
+// evaluated by priority against the client's current version. Returns nil if the
+// client is not pinned to any line.
+func (s *ReleaseLineService) ResolveForClient(appID, requested, currentVersion string) (*models.ReleaseLine, error) {
+	if requested != "" {
+		line, err := s.GetReleaseLine(appID, requested)
+		if err != nil {
+			return nil, err
+		}
+		if !line.IsActive {
+			return nil, fmt.Errorf("release line %s is %w", requested, ErrReleaseLineInactive)
+		}
+		return line, nil
+	}
+
+	var lines []*models.ReleaseLine
+	if err := s.db.Where("app_id = ? AND is_active = ? AND client_version_range != ''", appID, true).
+		Order("priority DESC, name").
+		Find(&lines).Error; err != nil {
+		return nil, fmt.Errorf("failed to get release lines: %w", err)
+	}
+
+	for _, line := range lines {
+		if ok, err := s.versionCmp.SatisfiesConstraint(currentVersion, line.ClientVersionRange); err == nil && ok {
+			return line, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Contains checks if a version belongs to the release line
+func (s *ReleaseLineService) Contains(line *models.ReleaseLine, version string) bool {
+	ok, err := s.versionCmp.SatisfiesConstraint(version, line.VersionRange)
+	return err == nil && ok
+}
+
+// validateRequest validates the version ranges of a release line request
+func (s *ReleaseLineService) validateRequest(req *models.ReleaseLineRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("release line name is required")
+	}
+	if !s.versionCmp.IsValidConstraint(req.VersionRange) {
+		return fmt.Errorf("invalid version_range: %s", req.VersionRange)
+	}
+	if req.ClientVersionRange != "" && !s.versionCmp.IsValidConstraint(req.ClientVersionRange) {
+		return fmt.Errorf("invalid client_version_range: %s", req.ClientVersionRange)
+	}
+	return nil
+}
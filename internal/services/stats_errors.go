@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/utils"
+	"gorm.io/gorm"
+)
+
+// installErrorSamples is the number of sample messages of an install error group
+const installErrorSamples = 3
+
+// GetTopInstallErrors returns the install failures of the period or date range of the
+// request grouped by application, version and error signature, the most frequent first
+func (s *StatsService) GetTopInstallErrors(req *models.StatsRequest, version string, limit int) (*models.InstallErrorsResponse, error) {
+	from, to, err := statsDateRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := statsDayBounds(from, to)
+	failures := func() *gorm.DB {
+		query := applyStatsFilter(s.db.Model(&models.UpdateStat{}), &req.StatsFilter).
+			Where("action = ? AND created_at >= ? AND created_at < ?", "failed", start, end)
+		if version != "" {
+			query = query.Where("version = ?", version)
+		}
+		return query
+	}
+
+	response := &models.InstallErrorsResponse{
+		From:   from,
+		To:     to,
+		Errors: []models.InstallErrorGroup{},
+	}
+	if err := failures().Count(&response.TotalFailures).Error; err != nil {
+		return nil, fmt.Errorf("failed to count install failures: %w", err)
+	}
+	if response.TotalFailures == 0 {
+		return response, nil
+	}
+
+	var groups []models.InstallErrorGroup
+	if err := failures().
+		Select("COALESCE(app_id, '') as app_id, version, COALESCE(error_signature, '') as signature, " +
+			"COUNT(*) as count, COUNT(DISTINCT client_id) as clients").
+		Group("COALESCE(app_id, ''), version, COALESCE(error_signature, '')").
+		Order("count DESC").
+		Limit(limit).
+		Scan(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to group install failures: %w", err)
+	}
+
+	for i := range groups {
+		group := &groups[i]
+		inGroup := func() *gorm.DB {
+			return failures().Where("COALESCE(app_id, '') = ? AND version = ? AND COALESCE(error_signature, '') = ?",
+				group.AppID, group.Version, group.Signature)
+		}
+
+		var first, last models.UpdateStat
+		if err := inGroup().Select("created_at").Order("created_at").First(&first).Error; err != nil {
+			return nil, fmt.Errorf("failed to get first install failure: %w", err)
+		}
+		if err := inGroup().Select("created_at").Order("created_at DESC").First(&last).Error; err != nil {
+			return nil, fmt.Errorf("failed to get last install failure: %w", err)
+		}
+		group.FirstSeen = first.CreatedAt
+		group.LastSeen = last.CreatedAt
+
+		type sample struct {
+			ErrorMessage string
+			LastID       uint
+		}
+		var samples []sample
+		if err := inGroup().
+			Select("error_message, MAX(id) as last_id").
+			Group("error_message").
+			Order("last_id DESC").
+			Limit(installErrorSamples).
+			Scan(&samples).Error; err != nil {
+			return nil, fmt.Errorf("failed to get install failure samples: %w", err)
+		}
+		group.Samples = make([]string, 0, len(samples))
+		for _, sample := range samples {
+			group.Samples = append(group.Samples, sample.ErrorMessage)
+		}
+	}
+
+	response.Errors = groups
+	return response, nil
+}
+
+// BackfillErrorSignatures sets the error signature of the stats recorded before signatures
+// were, batch by batch, and returns the number of stats updated
+func (s *StatsService) BackfillErrorSignatures() (int, error) {
+	updated := 0
+	var cursor uint
+	for {
+		var stats []models.UpdateStat
+		if err := s.db.Select("id, error_message").
+			Where("id > ? AND error_message IS NOT NULL AND error_message != ''", cursor).
+			Where("error_signature IS NULL OR error_signature = ''").
+			Order("id").
+			Limit(statsExportBatch).
+			Find(&stats).Error; err != nil {
+			return updated, fmt.Errorf("failed to get stats without error signature: %w", err)
+		}
+
+		for _, stat := range stats {
+			signature := utils.ErrorSignature(stat.ErrorMessage)
+			if signature == "" {
+				continue
+			}
+			if err := s.db.Model(&models.UpdateStat{}).
+				Where("id = ?", stat.ID).
+				UpdateColumn("error_signature", signature).Error; err != nil {
+				return updated, fmt.Errorf("failed to set error signature: %w", err)
+			}
+			updated++
+		}
+
+		if len(stats) < statsExportBatch {
+			return updated, nil
+		}
+		cursor = stats[len(stats)-1].ID
+	}
+}
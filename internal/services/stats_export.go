@@ -0,0 +1,215 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"gorm.io/gorm"
+)
+
+// statsExportBatch is the number of stats an export reads from the database at a time
+const statsExportBatch = 1000
+
+// statsExportColumns are the CSV columns of an update stats export
+var statsExportColumns = []string{
+	"id", "created_at", "app_id", "channel", "version", "client_id", "client_version",
+	"region", "ip_address", "user_agent", "action", "error_message", "error_signature", "event_id",
+}
+
+// StatsExport is a page of raw update stats to stream, see StatsService.ExportStats
+type StatsExport struct {
+	format string
+	query  func() *gorm.DB // Stats matching the filter of the export
+	cursor uint
+	lastID uint // ID of the last stat of the page, 0 if the page ends with the last stat
+
+	// NextCursor continues the export after this page, 0 if there are no more stats
+	NextCursor uint
+}
+
+// ExportStats prepares an export of the update stats matching the request. Only the bounds
+// of the page are read here, the stats are read batch by batch while the export is written.
+func (s *StatsService) ExportStats(req *models.StatsExportRequest) (*StatsExport, error) {
+	if req.Format != models.StatsExportCSV && req.Format != models.StatsExportNDJSON {
+		return nil, fmt.Errorf("invalid format %s, must be one of: csv, ndjson", req.Format)
+	}
+	if req.Limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d", req.Limit)
+	}
+
+	from, err := parseExportTime(req.From, false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseExportTime(req.To, true)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &StatsExport{
+		format: req.Format,
+		cursor: req.Cursor,
+	}
+	export.query = func() *gorm.DB {
+		query := applyStatsFilter(s.db.Model(&models.UpdateStat{}), &models.StatsFilter{AppID: req.AppID, Channel: req.Channel})
+		if req.Action != "" {
+			query = query.Where("action = ?", req.Action)
+		}
+		if req.Version != "" {
+			query = query.Where("version = ?", req.Version)
+		}
+		if !from.IsZero() {
+			query = query.Where("created_at >= ?", from)
+		}
+		if !to.IsZero() {
+			query = query.Where("created_at < ?", to)
+		}
+		return query
+	}
+
+	if req.Limit == 0 {
+		return export, nil
+	}
+
+	var ids []uint
+	if err := export.query().
+		Where("id > ?", req.Cursor).
+		Order("id").
+		Offset(req.Limit-1).
+		Limit(1).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to get export page: %w", err)
+	}
+	if len(ids) == 0 {
+		return export, nil
+	}
+	export.lastID = ids[0]
+
+	var more []uint
+	if err := export.query().
+		Where("id > ?", export.lastID).
+		Limit(1).
+		Pluck("id", &more).Error; err != nil {
+		return nil, fmt.Errorf("failed to get export page: %w", err)
+	}
+	if len(more) > 0 {
+		export.NextCursor = export.lastID
+	}
+
+	return export, nil
+}
+
+// parseExportTime parses an RFC 3339 time or a UTC day, the end of the day if end is set.
+// An empty value is the zero time.
+func parseExportTime(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	// Timestamps are stored in UTC, see statsDayBounds
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+
+	day, err := time.Parse(models.StatRollupDateFormat, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %s, expected RFC 3339 or YYYY-MM-DD", value)
+	}
+	if end {
+		return day.AddDate(0, 0, 1), nil
+	}
+	return day, nil
+}
+
+// Write writes the stats of the export to w, flushing it after every batch
+func (e *StatsExport) Write(w io.Writer) error {
+	var csvWriter *csv.Writer
+	var encoder *json.Encoder
+	if e.format == models.StatsExportCSV {
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(statsExportColumns); err != nil {
+			return err
+		}
+	} else {
+		encoder = json.NewEncoder(w)
+	}
+
+	cursor := e.cursor
+	for {
+		query := e.query().Where("id > ?", cursor)
+		if e.lastID > 0 {
+			query = query.Where("id <= ?", e.lastID)
+		}
+
+		var stats []models.UpdateStat
+		if err := query.Order("id").Limit(statsExportBatch).Find(&stats).Error; err != nil {
+			return fmt.Errorf("failed to get stats: %w", err)
+		}
+
+		for i := range stats {
+			stat := stats[i].ToResponse()
+			if stats[i].IPAddress == nil {
+				stat.IPAddress = ""
+			}
+
+			if csvWriter != nil {
+				if err := csvWriter.Write(statsExportRow(stat)); err != nil {
+					return err
+				}
+			} else if err := encoder.Encode(stat); err != nil {
+				return err
+			}
+		}
+
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return err
+			}
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		if len(stats) < statsExportBatch {
+			return nil
+		}
+		cursor = stats[len(stats)-1].ID
+	}
+}
+
+// statsExportRow returns the CSV row of a stat, in the order of statsExportColumns. Client
+// supplied cells are escaped against formula injection.
+func statsExportRow(stat *models.UpdateStatResponse) []string {
+	return []string{
+		strconv.FormatUint(uint64(stat.ID), 10),
+		stat.CreatedAt.UTC().Format(time.RFC3339Nano),
+		csvCell(stat.AppID),
+		csvCell(stat.Channel),
+		csvCell(stat.Version),
+		csvCell(stat.ClientID),
+		csvCell(stat.ClientVersion),
+		csvCell(stat.Region),
+		csvCell(stat.IPAddress),
+		csvCell(stat.UserAgent),
+		csvCell(stat.Action),
+		csvCell(stat.ErrorMessage),
+		csvCell(stat.ErrorSignature),
+		csvCell(stat.EventID),
+	}
+}
+
+// csvCell prefixes a value that spreadsheets would evaluate as a formula with a quote, so
+// that it is shown as text
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+)
+
+// funnelActions are the update stat actions of the stages of the update funnel
+var funnelActions = []string{"offer", "download", "success", "failed"}
+
+// funnelProgress holds when a client first reached each stage of the update to a version,
+// zero for stages it didn't reach
+type funnelProgress struct {
+	offered    time.Time
+	downloaded time.Time
+	succeeded  time.Time
+	failed     time.Time
+}
+
+// record keeps the first time of the stage of the action
+func (p *funnelProgress) record(action string, at time.Time) {
+	var stage *time.Time
+	switch action {
+	case "offer":
+		stage = &p.offered
+	case "download":
+		stage = &p.downloaded
+	case "success":
+		stage = &p.succeeded
+	case "failed":
+		stage = &p.failed
+	default:
+		return
+	}
+	if stage.IsZero() || at.Before(*stage) {
+		*stage = at
+	}
+}
+
+// GetFunnel reports for every released version of the application how many clients were
+// offered it, started the download and reported the installation within the period or date
+// range of the request. Events of a client are joined by client ID and version.
+func (s *StatsService) GetFunnel(req *models.StatsRequest, version string) (*models.FunnelResponse, error) {
+	if req.AppID == "" {
+		return nil, fmt.Errorf("invalid request, app_id is required")
+	}
+
+	from, to, err := statsDateRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Where("app_id = ? AND is_published = ?", req.AppID, true)
+	if req.Channel != "" {
+		query = query.Where("channel = ?", req.Channel)
+	}
+	if version != "" {
+		query = query.Where("version = ?", version)
+	}
+
+	var versions []models.Version
+	if err := query.Order("publish_time DESC").Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get released versions: %w", err)
+	}
+
+	response := &models.FunnelResponse{
+		AppID:    req.AppID,
+		From:     from,
+		To:       to,
+		Versions: []models.VersionFunnel{},
+	}
+	if len(versions) == 0 {
+		return response, nil
+	}
+
+	versionNumbers := make([]string, 0, len(versions))
+	for _, version := range versions {
+		versionNumbers = append(versionNumbers, version.Version)
+	}
+
+	start, end := statsDayBounds(from, to)
+	rows, err := s.db.Model(&models.UpdateStat{}).
+		Select("client_id, version, action, created_at").
+		Where("app_id = ? AND created_at >= ? AND created_at < ?", req.AppID, start, end).
+		Where("action IN ? AND version IN ? AND client_id != ''", funnelActions, versionNumbers).
+		Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get update events: %w", err)
+	}
+	defer rows.Close()
+
+	// Progress of the clients per version
+	progress := make(map[string]map[string]*funnelProgress)
+	for rows.Next() {
+		var stat models.UpdateStat
+		if err := s.db.ScanRows(rows, &stat); err != nil {
+			return nil, fmt.Errorf("failed to read update event: %w", err)
+		}
+
+		clients := progress[stat.Version]
+		if clients == nil {
+			clients = make(map[string]*funnelProgress)
+			progress[stat.Version] = clients
+		}
+		client := clients[stat.ClientID]
+		if client == nil {
+			client = &funnelProgress{}
+			clients[stat.ClientID] = client
+		}
+		client.record(stat.Action, stat.CreatedAt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read update events: %w", err)
+	}
+
+	for _, version := range versions {
+		funnel := buildFunnel(progress[version.Version])
+		funnel.Version = version.Version
+		funnel.Channel = version.Channel
+		funnel.PublishTime = version.PublishTime
+		response.Versions = append(response.Versions, funnel)
+	}
+
+	return response, nil
+}
+
+// buildFunnel counts the clients at every stage of the update to a version. Stages are
+// counted on their own, so that clients without offers, such as feed clients and clients
+// recorded before offers were, are included. Rates and medians follow the clients from
+// one stage to the next.
+func buildFunnel(clients map[string]*funnelProgress) models.VersionFunnel {
+	var funnel models.VersionFunnel
+	var offeredDownloaded, offeredSucceeded, downloadedSucceeded int64
+	var toDownload, toInstall []time.Duration
+
+	for _, client := range clients {
+		offered := !client.offered.IsZero()
+		downloaded := !client.downloaded.IsZero()
+		succeeded := !client.succeeded.IsZero()
+
+		if offered {
+			funnel.Offered++
+		}
+		if downloaded {
+			funnel.Downloaded++
+		}
+		switch {
+		case succeeded:
+			funnel.Succeeded++
+		case !client.failed.IsZero():
+			funnel.Failed++
+		}
+
+		if offered && downloaded {
+			offeredDownloaded++
+			// A client may have downloaded after an offer before the range only
+			if wait := client.downloaded.Sub(client.offered); wait >= 0 {
+				toDownload = append(toDownload, wait)
+			}
+		}
+		if offered && succeeded {
+			offeredSucceeded++
+		}
+		if !downloaded {
+			continue
+		}
+		if succeeded {
+			downloadedSucceeded++
+		}
+
+		result := client.succeeded
+		if result.IsZero() || (!client.failed.IsZero() && client.failed.Before(result)) {
+			result = client.failed
+		}
+		if wait := result.Sub(client.downloaded); !result.IsZero() && wait >= 0 {
+			toInstall = append(toInstall, wait)
+		}
+	}
+
+	funnel.DownloadRate = percentage(offeredDownloaded, funnel.Offered)
+	funnel.InstallRate = percentage(downloadedSucceeded, funnel.Downloaded)
+	funnel.ConversionRate = percentage(offeredSucceeded, funnel.Offered)
+	funnel.MedianOfferToDownload = medianSeconds(toDownload)
+	funnel.MedianDownloadToInstall = medianSeconds(toInstall)
+	return funnel
+}
+
+// percentage returns part of total in percent, 0 if total is 0
+func percentage(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// medianSeconds returns the median of the durations in whole seconds, 0 if there are none
+func medianSeconds(durations []time.Duration) int64 {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	middle := len(durations) / 2
+	median := durations[middle]
+	if len(durations)%2 == 0 {
+		median = (durations[middle-1] + durations[middle]) / 2
+	}
+	return int64(median.Seconds())
+}
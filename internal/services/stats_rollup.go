@@ -0,0 +1,225 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// rollupHistory is how far back the rollup job looks for days without rollups at start
+	rollupHistory = maxStatsDays * 24 * time.Hour
+
+	// defaultRollupInterval is the time between refreshes of the rollup job if none is set
+	defaultRollupInterval = 5 * time.Minute
+)
+
+// staleRollups holds the days that received stats after the rollup job last refreshed
+// them, such as telemetry sent late by clients that were offline
+var staleRollups = struct {
+	sync.Mutex
+	days map[string]bool
+}{days: make(map[string]bool)}
+
+// markRollupStale queues the day of t for the next refresh of the rollup job
+func markRollupStale(t time.Time) {
+	staleRollups.Lock()
+	staleRollups.days[t.UTC().Format(models.StatRollupDateFormat)] = true
+	staleRollups.Unlock()
+}
+
+// takeStaleRollups returns the queued days and empties the queue
+func takeStaleRollups() []string {
+	staleRollups.Lock()
+	defer staleRollups.Unlock()
+
+	days := make([]string, 0, len(staleRollups.days))
+	for day := range staleRollups.days {
+		days = append(days, day)
+	}
+	staleRollups.days = make(map[string]bool)
+	sort.Strings(days)
+	return days
+}
+
+// StartStatsRollup starts the background job keeping the daily stat rollups up to date and
+// returns a function that stops it. At start the job rolls up the days of the last year that
+// have no rollups, then every interval today and the days that received late telemetry.
+func StartStatsRollup(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = defaultRollupInterval
+	}
+
+	svc := NewStatsService()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		if err := svc.rollupMissingDays(time.Now().UTC().Add(-rollupHistory)); err != nil {
+			log.Printf("Failed to roll up past stats: %v", err)
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		lastDay := ""
+		for {
+			today := time.Now().UTC().Format(models.StatRollupDateFormat)
+			days := takeStaleRollups()
+			if lastDay != "" && lastDay != today {
+				// Complete the day that ended since the last refresh
+				days = append(days, lastDay)
+			}
+			days = append(days, today)
+			lastDay = today
+
+			for _, day := range days {
+				if err := svc.RollupDay(day); err != nil {
+					log.Printf("Failed to roll up stats of %s: %v", day, err)
+				}
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// rollupMissingDays rolls up the days from since until yesterday that have stats but no
+// rollups, or were rolled up before the rollups of distinct clients
+func (s *StatsService) rollupMissingDays(since time.Time) error {
+	var first models.UpdateStat
+	err := s.db.Select("created_at").
+		Where("created_at >= ?", since).
+		Order("created_at").
+		First(&first).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get first stat: %w", err)
+	}
+
+	var rolledUp []string
+	if err := s.db.Model(&models.StatRollup{}).
+		Where("action = ?", models.StatRollupAppClients).
+		Distinct("date").
+		Pluck("date", &rolledUp).Error; err != nil {
+		return fmt.Errorf("failed to get rolled up days: %w", err)
+	}
+	done := make(map[string]bool, len(rolledUp))
+	for _, day := range rolledUp {
+		done[day] = true
+	}
+
+	today := time.Now().UTC().Format(models.StatRollupDateFormat)
+	for day := first.CreatedAt.UTC(); ; day = day.AddDate(0, 0, 1) {
+		date := day.Format(models.StatRollupDateFormat)
+		if date >= today {
+			return nil
+		}
+		if done[date] {
+			continue
+		}
+		if err := s.RollupDay(date); err != nil {
+			return err
+		}
+	}
+}
+
+// RollupDays recomputes the rollups of the days from from to to, both inclusive and
+// formatted as models.StatRollupDateFormat
+func (s *StatsService) RollupDays(from, to string) (int, error) {
+	start, end, err := parseDateRange(from, to)
+	if err != nil {
+		return 0, err
+	}
+	if end.Sub(start) >= maxStatsDays*24*time.Hour {
+		return 0, fmt.Errorf("invalid date range, it must not exceed %d days", maxStatsDays)
+	}
+
+	days := 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if err := s.RollupDay(day.Format(models.StatRollupDateFormat)); err != nil {
+			return days, err
+		}
+		days++
+	}
+	return days, nil
+}
+
+// RollupDay recomputes the rollups of one UTC day from the raw update stats
+func (s *StatsService) RollupDay(date string) error {
+	if _, err := time.Parse(models.StatRollupDateFormat, date); err != nil {
+		return fmt.Errorf("invalid date %s", date)
+	}
+	start, end := statsDayBounds(date, date)
+
+	var rollups []models.StatRollup
+	if err := s.db.Model(&models.UpdateStat{}).
+		Select("COALESCE(app_id, '') as app_id, COALESCE(channel, '') as channel, version, "+
+			"COALESCE(region, '') as region, action, COUNT(*) as events, "+
+			"COUNT(DISTINCT client_id) as unique_clients").
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Group("app_id, channel, version, region, action").
+		Scan(&rollups).Error; err != nil {
+		return fmt.Errorf("failed to aggregate stats of %s: %w", date, err)
+	}
+
+	var channelClients, appClients []models.StatRollup
+	if err := s.db.Model(&models.UpdateStat{}).
+		Select("COALESCE(app_id, '') as app_id, COALESCE(channel, '') as channel, "+
+			"COUNT(DISTINCT client_id) as unique_clients").
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Group("app_id, channel").
+		Scan(&channelClients).Error; err != nil {
+		return fmt.Errorf("failed to count clients of %s: %w", date, err)
+	}
+	if err := s.db.Model(&models.UpdateStat{}).
+		Select("COALESCE(app_id, '') as app_id, COUNT(DISTINCT client_id) as unique_clients").
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Group("app_id").
+		Scan(&appClients).Error; err != nil {
+		return fmt.Errorf("failed to count clients of %s: %w", date, err)
+	}
+	for i := range channelClients {
+		channelClients[i].Action = models.StatRollupChannelClients
+	}
+	for i := range appClients {
+		appClients[i].Action = models.StatRollupAppClients
+	}
+	rollups = append(append(rollups, channelClients...), appClients...)
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("date = ?", date).Delete(&models.StatRollup{}).Error; err != nil {
+			return fmt.Errorf("failed to delete rollups of %s: %w", date, err)
+		}
+		if len(rollups) == 0 {
+			return nil
+		}
+
+		for i := range rollups {
+			rollups[i].ID = 0
+			rollups[i].Date = date
+		}
+		if err := tx.CreateInBatches(rollups, 500).Error; err != nil {
+			return fmt.Errorf("failed to create rollups of %s: %w", date, err)
+		}
+		return nil
+	})
+}
@@ -3,11 +3,14 @@ package services
 import (
 	"fmt"
 	"net"
+	"sort"
 	"time"
 
 	"github.com/Run-Panel/VerTree/internal/database"
 	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // StatsService handles statistics-related business logic
@@ -30,15 +33,23 @@ func (s *StatsService) RecordUpdateStat(req *models.UpdateStatRequest, clientIP
 		ipAddr = net.ParseIP(clientIP)
 	}
 
+	channel := req.Channel
+	if channel == "" {
+		channel = s.versionChannel(req.AppID, req.Version)
+	}
+
 	stat := &models.UpdateStat{
-		Version:       req.Version,
-		ClientID:      req.ClientID,
-		ClientVersion: req.ClientVersion,
-		Region:        req.Region,
-		IPAddress:     ipAddr,
-		UserAgent:     req.UserAgent,
-		Action:        req.Action,
-		ErrorMessage:  req.ErrorMessage,
+		AppID:          req.AppID,
+		Channel:        channel,
+		Version:        req.Version,
+		ClientID:       req.ClientID,
+		ClientVersion:  req.ClientVersion,
+		Region:         req.Region,
+		IPAddress:      ipAddr,
+		UserAgent:      req.UserAgent,
+		Action:         req.Action,
+		ErrorMessage:   req.ErrorMessage,
+		ErrorSignature: utils.ErrorSignature(req.ErrorMessage),
 	}
 
 	if err := s.db.Create(stat).Error; err != nil {
@@ -48,91 +59,187 @@ func (s *StatsService) RecordUpdateStat(req *models.UpdateStatRequest, clientIP
 	return nil
 }
 
-// GetStats retrieves statistics based on the request
-func (s *StatsService) GetStats(req *models.StatsRequest) (*models.StatsResponse, error) {
-	// Calculate time range
-	var startTime time.Time
-	now := time.Now()
+// versionChannel returns the channel of an application version, or "" if it is unknown
+func (s *StatsService) versionChannel(appID, version string) string {
+	if appID == "" || version == "" {
+		return ""
+	}
 
-	switch req.Period {
-	case "1d":
-		startTime = now.AddDate(0, 0, -1)
-	case "7d":
-		startTime = now.AddDate(0, 0, -7)
-	case "30d":
-		startTime = now.AddDate(0, 0, -30)
-	case "90d":
-		startTime = now.AddDate(0, 0, -90)
-	default:
-		startTime = now.AddDate(0, 0, -7) // Default to 7 days
+	var channel string
+	s.db.Model(&models.Version{}).
+		Where("app_id = ? AND version = ?", appID, version).
+		Limit(1).
+		Pluck("channel", &channel)
+	return channel
+}
+
+// RecordTelemetryBatch records a batch of client telemetry events of an application in one
+// transaction. Events are deduplicated by event ID, so clients can safely retry a batch.
+func (s *StatsService) RecordTelemetryBatch(appID string, req *models.TelemetryBatchRequest, clientIP string) (*models.TelemetryBatchResponse, error) {
+	var ipAddr net.IP
+	if clientIP != "" {
+		ipAddr = net.ParseIP(clientIP)
 	}
 
-	query := s.db.Model(&models.UpdateStat{}).Where("created_at >= ?", startTime)
+	now := time.Now().UTC()
+	channels := make(map[string]string)
+	stats := make([]*models.UpdateStat, 0, len(req.Events))
+	for i := range req.Events {
+		event := &req.Events[i]
+
+		clientID := event.ClientID
+		if clientID == "" {
+			clientID = req.ClientID
+		}
+		if clientID == "" {
+			return nil, fmt.Errorf("client_id is required for event %s", event.EventID)
+		}
+
+		action := models.TelemetryEventDownload
+		if event.Type == models.TelemetryEventInstall {
+			action = "success"
+			if !event.Success {
+				action = "failed"
+			}
+		}
+
+		// Clients may have clock skew, never record events in the future. Times are stored
+		// in UTC whatever offset the client sent, see statsDayBounds.
+		createdAt := now
+		if event.OccurredAt != nil && event.OccurredAt.Before(now) {
+			createdAt = event.OccurredAt.UTC()
+		}
+
+		channel, ok := channels[event.Version]
+		if !ok {
+			channel = s.versionChannel(appID, event.Version)
+			channels[event.Version] = channel
+		}
+
+		eventID := event.EventID
+		stats = append(stats, &models.UpdateStat{
+			AppID:          appID,
+			Channel:        channel,
+			Version:        event.Version,
+			ClientID:       clientID,
+			ClientVersion:  req.ClientVersion,
+			IPAddress:      ipAddr,
+			Action:         action,
+			ErrorMessage:   event.ErrorMessage,
+			ErrorSignature: utils.ErrorSignature(event.ErrorMessage),
+			EventID:        &eventID,
+			CreatedAt:      createdAt,
+		})
+	}
 
-	// Apply action filter if specified
-	if req.Action != "all" {
-		query = query.Where("action = ?", req.Action)
+	response := &models.TelemetryBatchResponse{
+		Results: make([]models.TelemetryEventResult, 0, len(stats)),
 	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, stat := range stats {
+			// Events already recorded by an earlier attempt conflict on the event ID of the
+			// application and client
+			result := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "app_id"}, {Name: "client_id"}, {Name: "event_id"}},
+				DoNothing: true,
+			}).Create(stat)
+			if result.Error != nil {
+				return result.Error
+			}
 
-	// Get total unique users
-	var totalUsers int64
-	if err := s.db.Model(&models.UpdateStat{}).
-		Where("created_at >= ?", startTime).
-		Distinct("client_id").
-		Count(&totalUsers).Error; err != nil {
-		return nil, fmt.Errorf("failed to count total users: %w", err)
+			status := models.TelemetryEventAccepted
+			if result.RowsAffected == 0 {
+				status = models.TelemetryEventDuplicate
+				response.Duplicates++
+			} else {
+				response.Accepted++
+			}
+			response.Results = append(response.Results, models.TelemetryEventResult{
+				EventID: *stat.EventID,
+				Status:  status,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record telemetry batch: %w", err)
 	}
 
-	// Get total downloads
-	var totalDownloads int64
-	if err := s.db.Model(&models.UpdateStat{}).
-		Where("created_at >= ? AND action = ?", startTime, "download").
-		Count(&totalDownloads).Error; err != nil {
-		return nil, fmt.Errorf("failed to count total downloads: %w", err)
+	// Events of past days are late for the rollups the background job refreshes
+	today := now.UTC().Format(models.StatRollupDateFormat)
+	for _, stat := range stats {
+		if stat.CreatedAt.UTC().Format(models.StatRollupDateFormat) < today {
+			markRollupStale(stat.CreatedAt)
+		}
 	}
 
-	// Calculate success rate
-	var successCount int64
-	var totalAttempts int64
+	return response, nil
+}
 
-	if err := s.db.Model(&models.UpdateStat{}).
-		Where("created_at >= ? AND action = ?", startTime, "success").
-		Count(&successCount).Error; err != nil {
-		return nil, fmt.Errorf("failed to count successful updates: %w", err)
+// maxStatsDays limits the number of days of a stats date range
+const maxStatsDays = 366
+
+// GetStats retrieves statistics based on the request from the daily stat rollups
+func (s *StatsService) GetStats(req *models.StatsRequest) (*models.StatsResponse, error) {
+	from, to, err := statsDateRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the unique users of the busiest day
+	peakDailyUsers, err := s.peakClients(&req.StatsFilter, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count peak daily users: %w", err)
+	}
+
+	// Get event counts per action
+	type ActionCount struct {
+		Action string
+		Count  int64
+	}
+
+	var actions []ActionCount
+	if err := s.rollupQuery(&req.StatsFilter, from, to).
+		Select("action, SUM(events) as count").
+		Group("action").
+		Scan(&actions).Error; err != nil {
+		return nil, fmt.Errorf("failed to count events: %w", err)
 	}
 
-	if err := s.db.Model(&models.UpdateStat{}).
-		Where("created_at >= ? AND action IN (?)", startTime, []string{"success", "failed"}).
-		Count(&totalAttempts).Error; err != nil {
-		return nil, fmt.Errorf("failed to count total attempts: %w", err)
+	counts := make(map[string]int64)
+	for _, action := range actions {
+		counts[action.Action] = action.Count
 	}
 
+	// Calculate success rate
 	var successRate float64
-	if totalAttempts > 0 {
-		successRate = float64(successCount) / float64(totalAttempts) * 100
+	if totalAttempts := counts["success"] + counts["failed"]; totalAttempts > 0 {
+		successRate = float64(counts["success"]) / float64(totalAttempts) * 100
 	}
 
 	// Get version distribution
-	versionDistribution, err := s.getVersionDistribution(startTime)
+	versionDistribution, err := s.getDistribution(&req.StatsFilter, from, to, "version")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get version distribution: %w", err)
 	}
 
 	// Get region distribution
-	regionDistribution, err := s.getRegionDistribution(startTime)
+	regionDistribution, err := s.getDistribution(&req.StatsFilter, from, to, "region")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get region distribution: %w", err)
 	}
 
 	// Get daily stats
-	dailyStats, err := s.getDailyStats(startTime)
+	dailyStats, err := s.getDailyStats(&req.StatsFilter, from, to)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get daily stats: %w", err)
 	}
 
 	return &models.StatsResponse{
-		TotalUsers:          totalUsers,
-		TotalDownloads:      totalDownloads,
+		From:                from,
+		To:                  to,
+		PeakDailyUsers:      peakDailyUsers,
+		TotalDownloads:      counts["download"],
 		SuccessRate:         successRate,
 		VersionDistribution: versionDistribution,
 		RegionDistribution:  regionDistribution,
@@ -140,64 +247,143 @@ func (s *StatsService) GetStats(req *models.StatsRequest) (*models.StatsResponse
 	}, nil
 }
 
-// getVersionDistribution gets version distribution statistics
-func (s *StatsService) getVersionDistribution(startTime time.Time) (map[string]int64, error) {
-	type VersionCount struct {
-		Version string
-		Count   int64
+// statsDateRange returns the first and last day of a stats request: its from and to dates,
+// or the days of its period ending today
+func statsDateRange(req *models.StatsRequest) (string, string, error) {
+	if req.From != "" || req.To != "" {
+		start, end, err := parseDateRange(req.From, req.To)
+		if err != nil {
+			return "", "", err
+		}
+		if end.Sub(start) >= maxStatsDays*24*time.Hour {
+			return "", "", fmt.Errorf("invalid date range, it must not exceed %d days", maxStatsDays)
+		}
+		return start.Format(models.StatRollupDateFormat), end.Format(models.StatRollupDateFormat), nil
 	}
 
-	var results []VersionCount
-	if err := s.db.Model(&models.UpdateStat{}).
-		Select("client_version as version, COUNT(DISTINCT client_id) as count").
-		Where("created_at >= ? AND client_version != ''", startTime).
-		Group("client_version").
-		Order("count DESC").
-		Scan(&results).Error; err != nil {
-		return nil, err
+	days := 7 // Default to 7 days
+	switch req.Period {
+	case "1d":
+		days = 1
+	case "30d":
+		days = 30
+	case "90d":
+		days = 90
 	}
 
-	distribution := make(map[string]int64)
-	for _, result := range results {
-		if result.Version != "" {
-			distribution[result.Version] = result.Count
-		}
+	today := time.Now().UTC()
+	return today.AddDate(0, 0, 1-days).Format(models.StatRollupDateFormat), today.Format(models.StatRollupDateFormat), nil
+}
+
+// parseDateRange parses the inclusive date range from to to
+func parseDateRange(from, to string) (time.Time, time.Time, error) {
+	if from == "" || to == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date range, both from and to are required")
 	}
 
-	return distribution, nil
+	start, err := time.Parse(models.StatRollupDateFormat, from)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid from date %s, expected YYYY-MM-DD", from)
+	}
+	end, err := time.Parse(models.StatRollupDateFormat, to)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid to date %s, expected YYYY-MM-DD", to)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date range, from is after to")
+	}
+	return start, end, nil
 }
 
-// getRegionDistribution gets region distribution statistics
-func (s *StatsService) getRegionDistribution(startTime time.Time) (map[string]int64, error) {
-	type RegionCount struct {
-		Region string
-		Count  int64
+// statsDayBounds returns the start of the day from and the end of the day to, both formatted
+// as models.StatRollupDateFormat, to select the stats of the days with. Timestamps are
+// stored in UTC (see database.Initialize) and SQLite compares them as text, so the bounds
+// must be in UTC too.
+func statsDayBounds(from, to string) (time.Time, time.Time) {
+	start, _ := time.Parse(models.StatRollupDateFormat, from)
+	end, _ := time.Parse(models.StatRollupDateFormat, to)
+	return start.UTC(), end.AddDate(0, 0, 1).UTC()
+}
+
+// statsQuery returns a query of the update stats recorded since startTime, restricted to
+// the application and channel of the filter
+func (s *StatsService) statsQuery(filter *models.StatsFilter, startTime time.Time) *gorm.DB {
+	return applyStatsFilter(s.db.Model(&models.UpdateStat{}).Where("created_at >= ?", startTime), filter)
+}
+
+// rollupQuery returns a query of the stat rollups of the days from from to to, restricted
+// to the application and channel of the filter
+func (s *StatsService) rollupQuery(filter *models.StatsFilter, from, to string) *gorm.DB {
+	return applyStatsFilter(s.db.Model(&models.StatRollup{}).Where("date >= ? AND date <= ?", from, to), filter)
+}
+
+// applyStatsFilter restricts a query to the application and channel of the filter
+func applyStatsFilter(query *gorm.DB, filter *models.StatsFilter) *gorm.DB {
+	if filter == nil {
+		return query
+	}
+	if filter.AppID != "" {
+		query = query.Where("app_id = ?", filter.AppID)
+	}
+	if filter.Channel != "" {
+		query = query.Where("channel = ?", filter.Channel)
+	}
+	return query
+}
+
+// peakClients returns the most distinct clients seen on one day of the range. Distinct
+// clients of several days can't be counted from daily rollups, and summing the days would
+// count a client once for every day it was seen.
+func (s *StatsService) peakClients(filter *models.StatsFilter, from, to string) (int64, error) {
+	// Clients are counted across channels unless the filter selects one
+	action := models.StatRollupAppClients
+	if filter != nil && filter.Channel != "" {
+		action = models.StatRollupChannelClients
+	}
+
+	var count int64
+	daily := s.rollupQuery(filter, from, to).
+		Select("date, SUM(unique_clients) as clients").
+		Where("action = ?", action).
+		Group("date")
+	err := s.db.Table("(?) as daily", daily).
+		Select("COALESCE(MAX(clients), 0)").
+		Scan(&count).Error
+	return count, err
+}
+
+// getDistribution gets the clients that checked for updates per version or region, the
+// counts of the busiest day of each. A client that changed channel or region within a day
+// is counted once for each.
+func (s *StatsService) getDistribution(filter *models.StatsFilter, from, to, column string) (map[string]int64, error) {
+	type ValueCount struct {
+		Value string
+		Count int64
 	}
 
-	var results []RegionCount
-	if err := s.db.Model(&models.UpdateStat{}).
-		Select("region, COUNT(DISTINCT client_id) as count").
-		Where("created_at >= ? AND region != ''", startTime).
-		Group("region").
-		Order("count DESC").
+	daily := s.rollupQuery(filter, from, to).
+		Select("date, "+column+" as value, SUM(unique_clients) as clients").
+		Where("action = ? AND "+column+" != ''", "check").
+		Group("date, " + column)
+
+	var results []ValueCount
+	if err := s.db.Table("(?) as daily", daily).
+		Select("value, MAX(clients) as count").
+		Group("value").
 		Scan(&results).Error; err != nil {
 		return nil, err
 	}
 
 	distribution := make(map[string]int64)
 	for _, result := range results {
-		if result.Region == "" {
-			distribution["unknown"] = result.Count
-		} else {
-			distribution[result.Region] = result.Count
-		}
+		distribution[result.Value] = result.Count
 	}
 
 	return distribution, nil
 }
 
 // getDailyStats gets daily statistics
-func (s *StatsService) getDailyStats(startTime time.Time) ([]models.DailyStat, error) {
+func (s *StatsService) getDailyStats(filter *models.StatsFilter, from, to string) ([]models.DailyStat, error) {
 	type DailyCount struct {
 		Date   string
 		Action string
@@ -205,11 +391,9 @@ func (s *StatsService) getDailyStats(startTime time.Time) ([]models.DailyStat, e
 	}
 
 	var results []DailyCount
-	if err := s.db.Model(&models.UpdateStat{}).
-		Select("DATE(created_at) as date, action, COUNT(*) as count").
-		Where("created_at >= ?", startTime).
-		Group("DATE(created_at), action").
-		Order("date").
+	if err := s.rollupQuery(filter, from, to).
+		Select("date, action, SUM(events) as count").
+		Group("date, action").
 		Scan(&results).Error; err != nil {
 		return nil, err
 	}
@@ -234,54 +418,111 @@ func (s *StatsService) getDailyStats(startTime time.Time) ([]models.DailyStat, e
 	}
 
 	// Convert map to slice and sort by date
-	var dailyStats []models.DailyStat
+	dailyStats := make([]models.DailyStat, 0, len(dailyMap))
 	for _, stat := range dailyMap {
 		dailyStats = append(dailyStats, *stat)
 	}
+	sort.Slice(dailyStats, func(i, j int) bool {
+		return dailyStats[i].Date < dailyStats[j].Date
+	})
 
 	return dailyStats, nil
 }
 
-// GetVersionDistribution gets version distribution for a specific period
-func (s *StatsService) GetVersionDistribution(period string) (map[string]int64, error) {
-	// Calculate time range
-	var startTime time.Time
-	now := time.Now()
-
-	switch period {
-	case "1d":
-		startTime = now.AddDate(0, 0, -1)
-	case "7d":
-		startTime = now.AddDate(0, 0, -7)
-	case "30d":
-		startTime = now.AddDate(0, 0, -30)
-	case "90d":
-		startTime = now.AddDate(0, 0, -90)
-	default:
-		startTime = now.AddDate(0, 0, -7) // Default to 7 days
+// GetVersionDistribution gets version distribution for the period or date range of the request
+func (s *StatsService) GetVersionDistribution(req *models.StatsRequest) (map[string]int64, error) {
+	from, to, err := statsDateRange(req)
+	if err != nil {
+		return nil, err
 	}
+	return s.getDistribution(&req.StatsFilter, from, to, "version")
+}
 
-	return s.getVersionDistribution(startTime)
+// GetRegionDistribution gets region distribution for the period or date range of the request
+func (s *StatsService) GetRegionDistribution(req *models.StatsRequest) (map[string]int64, error) {
+	from, to, err := statsDateRange(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.getDistribution(&req.StatsFilter, from, to, "region")
 }
 
-// GetRegionDistribution gets region distribution for a specific period
-func (s *StatsService) GetRegionDistribution(period string) (map[string]int64, error) {
-	// Calculate time range
-	var startTime time.Time
-	now := time.Now()
+// GetSupportReport reports how many clients of the inventory seen within the last days
+// are still running deprecated or end-of-life versions of the filtered applications and
+// channels
+func (s *StatsService) GetSupportReport(filter *models.StatsFilter, days int) (*models.SupportReportResponse, error) {
+	now := time.Now().UTC()
+	startTime := now.AddDate(0, 0, -days)
 
-	switch period {
-	case "1d":
-		startTime = now.AddDate(0, 0, -1)
-	case "7d":
-		startTime = now.AddDate(0, 0, -7)
-	case "30d":
-		startTime = now.AddDate(0, 0, -30)
-	case "90d":
-		startTime = now.AddDate(0, 0, -90)
-	default:
-		startTime = now.AddDate(0, 0, -7) // Default to 7 days
+	query := s.db.Where("(eol_at IS NOT NULL AND eol_at <= ?) OR (deprecated_at IS NOT NULL AND deprecated_at <= ?)", now, now)
+	if filter.AppID != "" {
+		query = query.Where("app_id = ?", filter.AppID)
+	}
+	if filter.Channel != "" {
+		query = query.Where("channel = ?", filter.Channel)
+	}
+
+	var versions []models.Version
+	if err := query.Order("app_id, version").Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get unsupported versions: %w", err)
+	}
+
+	report := &models.SupportReportResponse{
+		Days:     days,
+		Versions: []models.SupportReportEntry{},
+	}
+	if len(versions) == 0 {
+		return report, nil
+	}
+
+	versionNumbers := make([]string, 0, len(versions))
+	for _, version := range versions {
+		versionNumbers = append(versionNumbers, version.Version)
+	}
+
+	type VersionCount struct {
+		AppID   string
+		Version string
+		Count   int64
+	}
+
+	// Count the clients by the version they run now, so that clients that upgraded since are
+	// left out, per application, as the same version number of different applications is
+	// unrelated
+	query = s.db.Model(&models.Client{}).
+		Select("app_id, current_version as version, COUNT(*) as count").
+		Where("last_seen_at >= ? AND current_version IN ?", startTime, versionNumbers)
+	if filter.AppID != "" {
+		query = query.Where("app_id = ?", filter.AppID)
+	}
+
+	var results []VersionCount
+	if err := query.Group("app_id, current_version").Scan(&results).Error; err != nil {
+		return nil, fmt.Errorf("failed to count active clients: %w", err)
+	}
+
+	counts := make(map[string]int64)
+	for _, result := range results {
+		counts[result.AppID+"/"+result.Version] = result.Count
+	}
+
+	for _, version := range versions {
+		entry := models.SupportReportEntry{
+			AppID:         version.AppID,
+			Version:       version.Version,
+			Status:        version.SupportStatus(now),
+			DeprecatedAt:  version.DeprecatedAt,
+			EOLAt:         version.EOLAt,
+			ActiveClients: counts[version.AppID+"/"+version.Version],
+		}
+
+		if entry.Status == models.SupportStatusEOL {
+			report.ActiveClientsOnEOL += entry.ActiveClients
+		} else {
+			report.ActiveClientsOnDeprecated += entry.ActiveClients
+		}
+		report.Versions = append(report.Versions, entry)
 	}
 
-	return s.getRegionDistribution(startTime)
+	return report, nil
 }
@@ -0,0 +1,644 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/database"
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/utils"
+	"gorm.io/gorm"
+)
+
+// tufKeySecret encrypts the private role keys stored in the database, see SetTUFKeySecret
+var tufKeySecret string
+
+// tufMutex serializes metadata generation so that role versions increase without gaps or races
+var tufMutex sync.Mutex
+
+// tufLifetimes defines how long the metadata of each role is valid. Metadata is re-signed
+// once less than a quarter of its lifetime is left, so clients that see expired metadata
+// can tell that they are being served a frozen repository.
+var tufLifetimes = map[string]time.Duration{
+	models.TUFRoleRoot:      365 * 24 * time.Hour,
+	models.TUFRoleTargets:   90 * 24 * time.Hour,
+	models.TUFRoleSnapshot:  7 * 24 * time.Hour,
+	models.TUFRoleTimestamp: 24 * time.Hour,
+}
+
+// SetTUFKeySecret sets the secret used to encrypt TUF role keys
+func SetTUFKeySecret(secret string) {
+	tufKeySecret = secret
+}
+
+// TUFService maintains TUF (The Update Framework) repository metadata for applications:
+// root, targets, snapshot and timestamp roles signed with server-side Ed25519 keys
+type TUFService struct {
+	db *gorm.DB
+}
+
+// NewTUFService creates a new TUF service instance
+func NewTUFService() *TUFService {
+	return &TUFService{
+		db: database.DB,
+	}
+}
+
+// GetStatus returns the keys and current metadata versions of an application
+func (s *TUFService) GetStatus(appID string) (*models.TUFStatusResponse, error) {
+	if err := s.checkApplication(appID); err != nil {
+		return nil, err
+	}
+
+	var keys []*models.TUFKey
+	if err := s.db.Where("app_id = ?", appID).Order("role ASC, created_at DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to get TUF keys: %w", err)
+	}
+
+	status := &models.TUFStatusResponse{
+		AppID:    appID,
+		Keys:     make([]*models.TUFKeyResponse, 0, len(keys)),
+		Metadata: []*models.TUFMetadataInfo{},
+	}
+	for _, key := range keys {
+		status.Keys = append(status.Keys, key.ToResponse())
+	}
+
+	for _, role := range models.TUFRoles {
+		metadata, err := latestTUFMetadata(s.db, appID, role)
+		if err != nil {
+			return nil, err
+		}
+		if metadata != nil {
+			status.Metadata = append(status.Metadata, &models.TUFMetadataInfo{
+				Role:      role,
+				Version:   metadata.Version,
+				Expires:   metadata.Expires,
+				CreatedAt: metadata.CreatedAt,
+			})
+		}
+	}
+
+	return status, nil
+}
+
+// GetMetadata returns a metadata file of an application as served to clients: "root.json",
+// "targets.json", "snapshot.json", "timestamp.json" or a versioned root such as "2.root.json"
+// (used by clients to walk the chain of root key rotations). Expiring metadata is re-signed first.
+func (s *TUFService) GetMetadata(appID, file string) ([]byte, error) {
+	role := strings.TrimSuffix(file, ".json")
+	version := 0
+	if prefix, rest, found := strings.Cut(role, "."); found {
+		parsed, err := strconv.Atoi(prefix)
+		if err != nil || parsed < 1 || rest != models.TUFRoleRoot {
+			return nil, fmt.Errorf("metadata not found")
+		}
+		role, version = rest, parsed
+	}
+	if !strings.HasSuffix(file, ".json") || !models.IsValidTUFRole(role) {
+		return nil, fmt.Errorf("metadata not found")
+	}
+
+	if version > 0 {
+		var metadata models.TUFMetadata
+		if err := s.db.Where("app_id = ? AND role = ? AND version = ?", appID, role, version).First(&metadata).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("metadata not found")
+			}
+			return nil, fmt.Errorf("failed to get metadata: %w", err)
+		}
+		return []byte(metadata.Content), nil
+	}
+
+	current, err := currentTUFMetadata(s.db, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only take the lock to re-sign, serving current metadata doesn't need it
+	metadata := current[role]
+	if tufChainStale(current) || tufTimestampStale(current) {
+		if err := s.lockedRefresh(appID); err != nil {
+			return nil, err
+		}
+		if metadata, err = latestTUFMetadata(s.db, appID, role); err != nil {
+			return nil, err
+		}
+	}
+
+	if metadata == nil {
+		return nil, fmt.Errorf("metadata not found")
+	}
+	return []byte(metadata.Content), nil
+}
+
+// Regenerate signs new targets, snapshot and timestamp metadata for the currently published
+// versions of an application, creating role keys and root metadata on first use
+func (s *TUFService) Regenerate(appID string) error {
+	if err := s.checkApplication(appID); err != nil {
+		return err
+	}
+
+	tufMutex.Lock()
+	defer tufMutex.Unlock()
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.regenerate(tx, appID, false, nil)
+	})
+}
+
+// RotateKey replaces the key of a role with a newly generated one and publishes a new root
+// listing it. A new root key is cross-signed by the previous root key so clients can verify
+// the rotation with the root they already trust.
+func (s *TUFService) RotateKey(appID, role string) (*models.TUFKey, error) {
+	if !models.IsValidTUFRole(role) {
+		return nil, fmt.Errorf("invalid TUF role: %s", role)
+	}
+	if err := s.checkApplication(appID); err != nil {
+		return nil, err
+	}
+
+	tufMutex.Lock()
+	defer tufMutex.Unlock()
+
+	var newKey *models.TUFKey
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Make sure there is a root to rotate from
+		root, err := latestTUFMetadata(tx, appID, models.TUFRoleRoot)
+		if err != nil {
+			return err
+		}
+		if root == nil {
+			if err := s.regenerate(tx, appID, false, nil); err != nil {
+				return err
+			}
+		}
+
+		var oldKeys []*models.TUFKey
+		if err := tx.Where("app_id = ? AND role = ? AND is_active = ?", appID, role, true).Find(&oldKeys).Error; err != nil {
+			return fmt.Errorf("failed to get TUF keys: %w", err)
+		}
+
+		now := time.Now()
+		for _, key := range oldKeys {
+			if err := tx.Model(key).Updates(map[string]interface{}{"is_active": false, "revoked_at": now}).Error; err != nil {
+				return fmt.Errorf("failed to revoke TUF key: %w", err)
+			}
+		}
+
+		if newKey, err = generateTUFKey(tx, appID, role); err != nil {
+			return err
+		}
+
+		var crossSigners []*models.TUFKey
+		if role == models.TUFRoleRoot {
+			crossSigners = oldKeys
+		}
+		return s.regenerate(tx, appID, true, crossSigners)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newKey, nil
+}
+
+// checkApplication checks that an application exists
+func (s *TUFService) checkApplication(appID string) error {
+	var count int64
+	if err := s.db.Model(&models.Application{}).Where("app_id = ?", appID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to find application: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("application not found")
+	}
+	return nil
+}
+
+// lockedRefresh takes tufMutex and re-signs metadata that is missing or about to expire
+func (s *TUFService) lockedRefresh(appID string) error {
+	tufMutex.Lock()
+	defer tufMutex.Unlock()
+
+	return s.refresh(appID)
+}
+
+// refresh re-signs metadata that is missing or about to expire. Must be called with tufMutex
+// held; requests that waited for the lock find the metadata already re-signed.
+func (s *TUFService) refresh(appID string) error {
+	current, err := currentTUFMetadata(s.db, appID)
+	if err != nil {
+		return err
+	}
+
+	if tufChainStale(current) {
+		if err := s.checkApplication(appID); err != nil {
+			return err
+		}
+		return s.db.Transaction(func(tx *gorm.DB) error {
+			return s.regenerate(tx, appID, false, nil)
+		})
+	}
+
+	if tufTimestampStale(current) {
+		return s.db.Transaction(func(tx *gorm.DB) error {
+			keys, _, err := activeTUFKeys(tx, appID)
+			if err != nil {
+				return err
+			}
+			_, err = writeTUFTimestamp(tx, appID, keys[models.TUFRoleTimestamp], current[models.TUFRoleSnapshot])
+			return err
+		})
+	}
+
+	return nil
+}
+
+// currentTUFMetadata returns the newest metadata of every role, nil for roles without any
+func currentTUFMetadata(db *gorm.DB, appID string) (map[string]*models.TUFMetadata, error) {
+	current := make(map[string]*models.TUFMetadata)
+	for _, role := range models.TUFRoles {
+		metadata, err := latestTUFMetadata(db, appID, role)
+		if err != nil {
+			return nil, err
+		}
+		current[role] = metadata
+	}
+	return current, nil
+}
+
+// tufChainStale checks if the root, targets or snapshot metadata is missing or about to
+// expire. Anything but the timestamp expiring means the whole chain is re-signed.
+func tufChainStale(current map[string]*models.TUFMetadata) bool {
+	for _, role := range []string{models.TUFRoleRoot, models.TUFRoleTargets, models.TUFRoleSnapshot} {
+		if current[role] == nil || tufExpiring(current[role]) {
+			return true
+		}
+	}
+	return false
+}
+
+// tufTimestampStale checks if the timestamp metadata is missing or about to expire
+func tufTimestampStale(current map[string]*models.TUFMetadata) bool {
+	return current[models.TUFRoleTimestamp] == nil || tufExpiring(current[models.TUFRoleTimestamp])
+}
+
+// regenerate writes new metadata for all roles. A new root is only written if there is none,
+// role keys were created or rotated (forceRoot), or the current root is about to expire.
+func (s *TUFService) regenerate(tx *gorm.DB, appID string, forceRoot bool, crossSigners []*models.TUFKey) error {
+	keys, created, err := activeTUFKeys(tx, appID)
+	if err != nil {
+		return err
+	}
+
+	root, err := latestTUFMetadata(tx, appID, models.TUFRoleRoot)
+	if err != nil {
+		return err
+	}
+	if root == nil || created || forceRoot || tufExpiring(root) {
+		if err := writeTUFRoot(tx, appID, keys, crossSigners); err != nil {
+			return err
+		}
+	}
+
+	targets, err := s.writeTargets(tx, appID, keys[models.TUFRoleTargets])
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := writeTUFSnapshot(tx, appID, keys[models.TUFRoleSnapshot], targets)
+	if err != nil {
+		return err
+	}
+
+	_, err = writeTUFTimestamp(tx, appID, keys[models.TUFRoleTimestamp], snapshot)
+	return err
+}
+
+// writeTargets signs targets metadata listing the downloads of all published versions
+func (s *TUFService) writeTargets(tx *gorm.DB, appID string, key *models.TUFKey) (*models.TUFMetadata, error) {
+	var versions []*models.Version
+	if err := tx.Preload("Artifacts").
+		Where("app_id = ? AND is_published = ?", appID, true).
+		Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get published versions: %w", err)
+	}
+
+	targets := make(map[string]models.TUFTargetFile)
+	for _, version := range versions {
+		custom := models.TUFTargetCustom{
+			Version:  version.Version,
+			Channel:  version.Channel,
+			URL:      version.FileURL,
+			IsForced: version.IsForced,
+		}
+		if hashes := tufHashes(version.FileChecksum, ""); len(hashes) > 0 {
+			targetPath := path.Join(version.Channel, version.Version, tufFileName("", version.FileURL))
+			targets[targetPath] = models.TUFTargetFile{Length: version.FileSize, Hashes: hashes, Custom: &custom}
+		} else {
+			log.Printf("TUF: version %s of %s has no usable checksum, not listed in targets", version.Version, appID)
+		}
+
+		for _, artifact := range version.Artifacts {
+			hashes := tufHashes(artifact.FileChecksum, artifact.SHA512)
+			if len(hashes) == 0 {
+				continue
+			}
+			platform := artifact.OS
+			if artifact.Arch != "" {
+				platform += "-" + artifact.Arch
+			}
+			artifactCustom := custom
+			artifactCustom.OS = artifact.OS
+			artifactCustom.Arch = artifact.Arch
+			artifactCustom.URL = artifact.FileURL
+
+			targetPath := path.Join(version.Channel, version.Version, platform, tufFileName(artifact.FileName, artifact.FileURL))
+			targets[targetPath] = models.TUFTargetFile{Length: artifact.FileSize, Hashes: hashes, Custom: &artifactCustom}
+		}
+	}
+
+	return writeTUFMetadata(tx, appID, models.TUFRoleTargets, func(version int, expires string) interface{} {
+		return models.TUFTargets{
+			Type:        models.TUFRoleTargets,
+			SpecVersion: models.TUFSpecVersion,
+			Version:     version,
+			Expires:     expires,
+			Targets:     targets,
+		}
+	}, key)
+}
+
+// writeTUFRoot signs root metadata listing the active keys of all roles
+func writeTUFRoot(tx *gorm.DB, appID string, keys map[string]*models.TUFKey, crossSigners []*models.TUFKey) error {
+	publicKeys := make(map[string]models.TUFPublicKey)
+	roles := make(map[string]models.TUFRoleKeys)
+	for _, role := range models.TUFRoles {
+		key := keys[role]
+		publicKeys[key.KeyID] = tufPublicKey(key.PublicKey)
+		roles[role] = models.TUFRoleKeys{KeyIDs: []string{key.KeyID}, Threshold: 1}
+	}
+
+	signers := append([]*models.TUFKey{keys[models.TUFRoleRoot]}, crossSigners...)
+	_, err := writeTUFMetadata(tx, appID, models.TUFRoleRoot, func(version int, expires string) interface{} {
+		return models.TUFRoot{
+			Type:               models.TUFRoleRoot,
+			SpecVersion:        models.TUFSpecVersion,
+			Version:            version,
+			Expires:            expires,
+			ConsistentSnapshot: false,
+			Keys:               publicKeys,
+			Roles:              roles,
+		}
+	}, signers...)
+	return err
+}
+
+// writeTUFSnapshot signs snapshot metadata pinning the current targets version
+func writeTUFSnapshot(tx *gorm.DB, appID string, key *models.TUFKey, targets *models.TUFMetadata) (*models.TUFMetadata, error) {
+	return writeTUFMetadata(tx, appID, models.TUFRoleSnapshot, func(version int, expires string) interface{} {
+		return models.TUFSnapshot{
+			Type:        models.TUFRoleSnapshot,
+			SpecVersion: models.TUFSpecVersion,
+			Version:     version,
+			Expires:     expires,
+			Meta: map[string]models.TUFMetaFile{
+				"targets.json": {Version: targets.Version},
+			},
+		}
+	}, key)
+}
+
+// writeTUFTimestamp signs timestamp metadata pinning the current snapshot by version and hash
+func writeTUFTimestamp(tx *gorm.DB, appID string, key *models.TUFKey, snapshot *models.TUFMetadata) (*models.TUFMetadata, error) {
+	hash := sha256.Sum256([]byte(snapshot.Content))
+	return writeTUFMetadata(tx, appID, models.TUFRoleTimestamp, func(version int, expires string) interface{} {
+		return models.TUFTimestamp{
+			Type:        models.TUFRoleTimestamp,
+			SpecVersion: models.TUFSpecVersion,
+			Version:     version,
+			Expires:     expires,
+			Meta: map[string]models.TUFMetaFile{
+				"snapshot.json": {
+					Version: snapshot.Version,
+					Length:  int64(len(snapshot.Content)),
+					Hashes:  map[string]string{"sha256": hex.EncodeToString(hash[:])},
+				},
+			},
+		}
+	}, key)
+}
+
+// writeTUFMetadata signs and stores the next version of a role's metadata. build returns
+// the signed part for the given version and expiry.
+func writeTUFMetadata(tx *gorm.DB, appID, role string, build func(version int, expires string) interface{}, signers ...*models.TUFKey) (*models.TUFMetadata, error) {
+	previous, err := latestTUFMetadata(tx, appID, role)
+	if err != nil {
+		return nil, err
+	}
+	version := 1
+	if previous != nil {
+		version = previous.Version + 1
+	}
+
+	expires := time.Now().UTC().Add(tufLifetimes[role]).Truncate(time.Second)
+	signed := build(version, expires.Format(models.TUFExpiresFormat))
+
+	payload, err := utils.CanonicalJSON(signed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s metadata: %w", role, err)
+	}
+
+	envelope := models.TUFSignedMetadata{Signed: signed, Signatures: []models.TUFSignature{}}
+	for _, signer := range signers {
+		privateKey, err := decryptTUFKey(signer)
+		if err != nil {
+			return nil, err
+		}
+		envelope.Signatures = append(envelope.Signatures, models.TUFSignature{
+			KeyID: signer.KeyID,
+			Sig:   hex.EncodeToString(ed25519.Sign(privateKey, payload)),
+		})
+	}
+
+	content, err := utils.CanonicalJSON(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s metadata: %w", role, err)
+	}
+
+	metadata := &models.TUFMetadata{
+		AppID:   appID,
+		Role:    role,
+		Version: version,
+		Expires: expires,
+		Content: string(content),
+	}
+	if err := tx.Create(metadata).Error; err != nil {
+		return nil, fmt.Errorf("failed to save %s metadata: %w", role, err)
+	}
+
+	return metadata, nil
+}
+
+// latestTUFMetadata returns the newest metadata of a role, or nil if there is none
+func latestTUFMetadata(db *gorm.DB, appID, role string) (*models.TUFMetadata, error) {
+	var metadata models.TUFMetadata
+	if err := db.Where("app_id = ? AND role = ?", appID, role).Order("version DESC").First(&metadata).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s metadata: %w", role, err)
+	}
+	return &metadata, nil
+}
+
+// tufExpiring checks if less than a quarter of the lifetime of metadata is left
+func tufExpiring(metadata *models.TUFMetadata) bool {
+	return time.Until(metadata.Expires) < tufLifetimes[metadata.Role]/4
+}
+
+// activeTUFKeys returns the active key of each role, generating missing keys.
+// created reports whether any key was generated.
+func activeTUFKeys(tx *gorm.DB, appID string) (keys map[string]*models.TUFKey, created bool, err error) {
+	var active []*models.TUFKey
+	if err := tx.Where("app_id = ? AND is_active = ?", appID, true).Order("created_at DESC").Find(&active).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to get TUF keys: %w", err)
+	}
+
+	keys = make(map[string]*models.TUFKey)
+	for _, key := range active {
+		if keys[key.Role] == nil {
+			keys[key.Role] = key
+		}
+	}
+
+	for _, role := range models.TUFRoles {
+		if keys[role] != nil {
+			continue
+		}
+		if keys[role], err = generateTUFKey(tx, appID, role); err != nil {
+			return nil, false, err
+		}
+		created = true
+	}
+
+	return keys, created, nil
+}
+
+// generateTUFKey generates and stores a new Ed25519 key for a role
+func generateTUFKey(tx *gorm.DB, appID, role string) (*models.TUFKey, error) {
+	if tufKeySecret == "" {
+		return nil, fmt.Errorf("TUF key secret is not configured, set TUF_KEY_SECRET")
+	}
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TUF key: %w", err)
+	}
+
+	encrypted, err := utils.EncryptSecret(privateKey.Seed(), tufKeySecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt TUF key: %w", err)
+	}
+
+	publicHex := hex.EncodeToString(publicKey)
+	keyID, err := tufKeyID(publicHex)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &models.TUFKey{
+		AppID:      appID,
+		Role:       role,
+		KeyID:      keyID,
+		PublicKey:  publicHex,
+		PrivateKey: encrypted,
+		IsActive:   true,
+	}
+	if err := tx.Create(key).Error; err != nil {
+		return nil, fmt.Errorf("failed to save TUF key: %w", err)
+	}
+
+	return key, nil
+}
+
+// decryptTUFKey decrypts the private key of a TUF key
+func decryptTUFKey(key *models.TUFKey) (ed25519.PrivateKey, error) {
+	seed, err := utils.DecryptSecret(key.PrivateKey, tufKeySecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt TUF %s key %s: %w", key.Role, key.KeyID, err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid TUF %s key %s", key.Role, key.KeyID)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// tufPublicKey returns the root metadata representation of a hex encoded Ed25519 public key
+func tufPublicKey(publicHex string) models.TUFPublicKey {
+	return models.TUFPublicKey{
+		KeyType: models.TUFKeyTypeEd25519,
+		Scheme:  models.TUFKeyTypeEd25519,
+		KeyVal:  models.TUFPublicKeyVal{Public: publicHex},
+	}
+}
+
+// tufKeyID computes the TUF key ID: the SHA256 of the canonical JSON of the public key
+func tufKeyID(publicHex string) (string, error) {
+	encoded, err := utils.CanonicalJSON(tufPublicKey(publicHex))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode TUF key: %w", err)
+	}
+	hash := sha256.Sum256(encoded)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// tufHashes converts the checksums known for a file into TUF target hashes. checksum is
+// "sha256:<hex>", "sha512:<hex>" or a bare hex digest; sha512Base64 is the electron-updater
+// style SHA512 of artifacts.
+func tufHashes(checksum, sha512Base64 string) map[string]string {
+	hashes := make(map[string]string)
+
+	algorithm, digest, found := strings.Cut(strings.ToLower(strings.TrimSpace(checksum)), ":")
+	if !found {
+		algorithm, digest = "", algorithm
+	}
+	if decoded, err := hex.DecodeString(digest); err == nil {
+		switch {
+		case len(decoded) == sha256.Size && (algorithm == "" || algorithm == "sha256"):
+			hashes["sha256"] = digest
+		case len(decoded) == sha512.Size && (algorithm == "" || algorithm == "sha512"):
+			hashes["sha512"] = digest
+		}
+	}
+
+	if decoded, err := base64.StdEncoding.DecodeString(sha512Base64); err == nil && len(decoded) == sha512.Size {
+		hashes["sha512"] = hex.EncodeToString(decoded)
+	}
+
+	return hashes
+}
+
+// tufFileName returns the file name of a target, falling back to the last segment of its URL
+func tufFileName(fileName, fileURL string) string {
+	if fileName != "" {
+		return path.Base(fileName)
+	}
+	if parsed, err := url.Parse(fileURL); err == nil && parsed.Path != "" {
+		return path.Base(parsed.Path)
+	}
+	return "download"
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/models"
+	"gorm.io/gorm"
+)
+
+// ExplainUpdate runs the update decision for a simulated update check and returns the trace
+// of its steps. Unlike CheckUpdate, nothing is recorded: no statistics, no client inventory
+// and no server load. Fields left empty are filled in from the inventory of the client.
+func (s *UpdateService) ExplainUpdate(appID string, req *models.ExplainUpdateRequest) (*models.ExplainUpdateResponse, error) {
+	var app models.Application
+	if err := s.db.Where("app_id = ?", appID).First(&app).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("application not found")
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	check := req.CheckUpdateRequest
+	check.AppID = appID
+	trace := &models.DecisionTrace{}
+
+	if check.ClientID != "" {
+		client, err := s.clientSvc.GetClient(appID, check.ClientID)
+		switch {
+		case err == nil:
+			detail := fmt.Sprintf("client was last seen %s", client.LastSeenAt.Format(time.RFC3339))
+			if filled := fillFromInventory(&check, client); len(filled) > 0 {
+				detail += ", filled in from the inventory: " + strings.Join(filled, ", ")
+			}
+			trace.Add("client", models.DecisionStepPassed, detail)
+		case errors.Is(err, ErrClientNotFound):
+			trace.Add("client", models.DecisionStepSkipped, "client is not in the inventory")
+		default:
+			return nil, err
+		}
+	}
+
+	if check.CurrentVersion == "" || check.Channel == "" {
+		return nil, fmt.Errorf("current_version and channel are required unless the client is in the inventory")
+	}
+
+	response := &models.ExplainUpdateResponse{Request: &check}
+
+	if !app.IsActive {
+		trace.Add("application", models.DecisionStepFailed, fmt.Sprintf("application %s is not active", appID))
+		response.Outcome = models.DecisionOutcomeError
+		response.ErrorCode = models.ErrCodeAppInactive
+		response.Steps = trace.Steps
+		return response, nil
+	}
+	trace.Add("application", models.DecisionStepPassed, fmt.Sprintf("application %s is active", appID))
+
+	decision, err := s.decideUpdate(&check, req.APIVersion == "v2", trace)
+	if err != nil {
+		var clientErr *models.ClientError
+		if !errors.As(err, &clientErr) {
+			return nil, err
+		}
+		response.Outcome = models.DecisionOutcomeError
+		response.ErrorCode = clientErr.Code
+		response.ReasonDetail = clientErr.Message
+		response.Steps = trace.Steps
+		return response, nil
+	}
+
+	decision.Response.SupportStatus = s.getSupportStatus(appID, check.CurrentVersion)
+
+	response.Outcome = models.DecisionOutcomeNoUpdate
+	if decision.Response.HasUpdate {
+		response.Outcome = models.DecisionOutcomeUpdate
+	}
+	response.Reason = decision.Reason
+	response.ReasonDetail = decision.Detail
+	response.Response = decision.Response
+	response.Steps = trace.Steps
+	return response, nil
+}
+
+// fillFromInventory fills the empty fields of a simulated update check with what the
+// client reported last, and returns the names of the filled fields
+func fillFromInventory(req *models.CheckUpdateRequest, client *models.Client) []string {
+	var filled []string
+	fill := func(field *string, value, name string) {
+		if *field == "" && value != "" {
+			*field = value
+			filled = append(filled, name)
+		}
+	}
+
+	fill(&req.CurrentVersion, client.CurrentVersion, "current_version")
+	fill(&req.Channel, client.Channel, "channel")
+	fill(&req.OS, client.OS, "os")
+	fill(&req.Arch, client.Arch, "arch")
+	fill(&req.Region, client.Region, "region")
+	return filled
+}
@@ -1,9 +1,14 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"net/http"
+	"time"
 
 	"github.com/Run-Panel/VerTree/internal/database"
+	"github.com/Run-Panel/VerTree/internal/metrics"
 	"github.com/Run-Panel/VerTree/internal/models"
 	"github.com/Run-Panel/VerTree/internal/utils"
 	"gorm.io/gorm"
@@ -15,6 +20,8 @@ type UpdateService struct {
 	versionSvc *VersionService
 	channelSvc *ChannelService
 	statsSvc   *StatsService
+	clientSvc  *ClientService
+	lineSvc    *ReleaseLineService
 	versionCmp *utils.VersionComparer
 }
 
@@ -25,81 +32,258 @@ func NewUpdateService() *UpdateService {
 		versionSvc: NewVersionService(),
 		channelSvc: NewChannelService(),
 		statsSvc:   NewStatsService(),
+		clientSvc:  NewClientService(),
+		lineSvc:    NewReleaseLineService(),
 		versionCmp: utils.NewVersionComparer(),
 	}
 }
 
 // CheckUpdate checks for available updates
 func (s *UpdateService) CheckUpdate(req *models.CheckUpdateRequest, clientIP string) (*models.CheckUpdateResponse, error) {
+	decision, err := s.checkUpdate(req, clientIP, false)
+	if err != nil {
+		return nil, err
+	}
+	return decision.Response, nil
+}
+
+// CheckUpdateV2 checks for available updates for the client API v2. Errors the client can
+// react to are *models.ClientError, and the decision tells why no update is offered.
+// Unlike CheckUpdate, a version with builds for other platforms only is not offered.
+func (s *UpdateService) CheckUpdateV2(req *models.CheckUpdateRequest, clientIP string) (*models.UpdateDecision, error) {
+	return s.checkUpdate(req, clientIP, true)
+}
+
+// checkUpdate validates the application, records the check and decides on the update
+func (s *UpdateService) checkUpdate(req *models.CheckUpdateRequest, clientIP string, requirePlatformBuild bool) (*models.UpdateDecision, error) {
 	// Validate that the app exists and is active
 	var app models.Application
-	if err := s.db.Where("app_id = ? AND is_active = ?", req.AppID, true).First(&app).Error; err != nil {
-		return nil, fmt.Errorf("application not found or inactive: %s", req.AppID)
+	if err := s.db.Where("app_id = ?", req.AppID).First(&app).Error; err != nil || !app.IsActive {
+		clientErr := &models.ClientError{
+			Status:  http.StatusForbidden,
+			Code:    models.ErrCodeAppInactive,
+			Message: fmt.Sprintf("application not found or inactive: %s", req.AppID),
+		}
+		if err != nil {
+			clientErr.Status, clientErr.Code = http.StatusNotFound, models.ErrCodeAppNotFound
+		}
+		return nil, clientErr
+	}
+	checkLoad.record()
+
+	decision, err := s.decideUpdate(req, requirePlatformBuild, nil)
+
+	// Record stat asynchronously (don't fail the request if this fails)
+	offeredVersion := ""
+	if err == nil && decision.Response.HasUpdate {
+		offeredVersion = decision.Response.LatestVersion
+	}
+	go s.recordCheck(req, clientIP, offeredVersion)
+
+	if err != nil {
+		metrics.UpdateChecks.WithLabelValues(req.AppID, metrics.CheckOutcomeError).Inc()
+		return nil, err
 	}
 
-	// Record the check action
+	outcome := decision.Reason
+	if decision.Response.HasUpdate {
+		outcome = metrics.CheckOutcomeUpdate
+	}
+	metrics.UpdateChecks.WithLabelValues(req.AppID, outcome).Inc()
+
+	// Tell the client whether its current version is still supported
+	decision.Response.SupportStatus = s.getSupportStatus(req.AppID, req.CurrentVersion)
+
+	// Tell the client when to check again, so that the server can shed load
+	decision.Response.NextCheckAfter = nextCheckAfter(s.db, req.AppID, req.Channel)
+
+	return decision, nil
+}
+
+// recordCheck records an update check in the statistics and the client inventory. The
+// offer of an update, the first stage of the update funnel, is recorded the first time the
+// version is offered to the client.
+func (s *UpdateService) recordCheck(req *models.CheckUpdateRequest, clientIP, offeredVersion string) {
 	statReq := &models.UpdateStatRequest{
+		AppID:         req.AppID,
+		Channel:       req.Channel,
 		Version:       req.CurrentVersion,
 		ClientID:      req.ClientID,
 		ClientVersion: req.CurrentVersion,
 		Region:        req.Region,
 		Action:        "check",
 	}
+	if err := s.statsSvc.RecordUpdateStat(statReq, clientIP); err != nil {
+		// Log error but don't fail the request
+		log.Printf("Failed to record update stat: %v", err)
+	}
+	if err := s.clientSvc.RecordCheck(req, clientIP); err != nil {
+		log.Printf("Failed to update client inventory: %v", err)
+		return
+	}
 
-	// Record stat asynchronously (don't fail the request if this fails)
-	go func() {
-		if err := s.statsSvc.RecordUpdateStat(statReq, clientIP); err != nil {
-			// Log error but don't fail the request
-			fmt.Printf("Failed to record update stat: %v\n", err)
-		}
-	}()
+	if offeredVersion == "" {
+		return
+	}
+	first, err := s.clientSvc.RecordOffer(req.AppID, req.ClientID, offeredVersion)
+	if err != nil {
+		log.Printf("Failed to update client inventory: %v", err)
+		return
+	}
+	if !first {
+		return
+	}
+
+	statReq.Version = offeredVersion
+	statReq.Action = "offer"
+	if err := s.statsSvc.RecordUpdateStat(statReq, clientIP); err != nil {
+		log.Printf("Failed to record update stat: %v", err)
+	}
+}
 
+// decideUpdate decides which update, if any, is offered to the client. The steps of the
+// decision are recorded in trace unless it is nil.
+func (s *UpdateService) decideUpdate(req *models.CheckUpdateRequest, requirePlatformBuild bool, trace *models.DecisionTrace) (*models.UpdateDecision, error) {
 	// Validate channel is enabled for this specific app
 	if err := s.channelSvc.ValidateChannelForApp(req.AppID, req.Channel); err != nil {
-		return nil, err
+		if !errors.Is(err, ErrChannelNotEnabled) {
+			return nil, err
+		}
+		clientErr := &models.ClientError{Status: http.StatusForbidden, Code: models.ErrCodeChannelDisabled, Message: err.Error()}
+		if _, lookupErr := s.channelSvc.GetChannelByName(req.Channel); lookupErr != nil {
+			clientErr.Status, clientErr.Code = http.StatusNotFound, models.ErrCodeChannelNotFound
+		}
+		trace.Add("channel", models.DecisionStepFailed, err.Error())
+		return nil, clientErr
 	}
 
 	// Get the channel details
 	channel, err := s.channelSvc.GetChannelByName(req.Channel)
 	if err != nil {
-		return nil, fmt.Errorf("channel not found: %w", err)
+		trace.Add("channel", models.DecisionStepFailed, fmt.Sprintf("channel %s does not exist", req.Channel))
+		return nil, &models.ClientError{
+			Status:  http.StatusNotFound,
+			Code:    models.ErrCodeChannelNotFound,
+			Message: fmt.Sprintf("channel not found: %v", err),
+		}
 	}
 
 	if !channel.IsActive {
-		return nil, fmt.Errorf("channel %s is not active", req.Channel)
+		trace.Add("channel", models.DecisionStepFailed, fmt.Sprintf("channel %s is not active", req.Channel))
+		return nil, &models.ClientError{
+			Status:  http.StatusForbidden,
+			Code:    models.ErrCodeChannelInactive,
+			Message: fmt.Sprintf("channel %s is not active", req.Channel),
+		}
 	}
+	trace.Add("channel", models.DecisionStepPassed, fmt.Sprintf("channel %s is active and enabled for the application", req.Channel))
 
-	// Get latest version for the app and channel
-	latestVersion, err := s.versionSvc.GetLatestVersionForApp(req.AppID, req.Channel)
+	// Resolve the release line the client is pinned to, if any
+	line, err := s.lineSvc.ResolveForClient(req.AppID, req.ReleaseLine, req.CurrentVersion)
 	if err != nil {
-		// No published version available
-		return &models.CheckUpdateResponse{
-			HasUpdate: false,
-		}, nil
+		trace.Add("release_line", models.DecisionStepFailed, err.Error())
+		switch {
+		case errors.Is(err, ErrReleaseLineNotFound):
+			return nil, &models.ClientError{Status: http.StatusNotFound, Code: models.ErrCodeReleaseLineNotFound, Message: err.Error()}
+		case errors.Is(err, ErrReleaseLineInactive):
+			return nil, &models.ClientError{Status: http.StatusForbidden, Code: models.ErrCodeReleaseLineInactive, Message: err.Error()}
+		}
+		return nil, err
+	}
+	if line != nil {
+		trace.Add("release_line", models.DecisionStepPassed, fmt.Sprintf("only versions in release line %s (%s) are considered", line.Name, line.VersionRange))
+	} else {
+		trace.Add("release_line", models.DecisionStepSkipped, "the client is not pinned to a release line")
+	}
+
+	noUpdate := func(reason, detail string) *models.UpdateDecision {
+		return &models.UpdateDecision{
+			Response: &models.CheckUpdateResponse{HasUpdate: false},
+			Reason:   reason,
+			Detail:   detail,
+		}
+	}
+
+	// Get the highest published version the client is compatible with
+	latestVersion, incompatibility, err := s.selectCompatibleVersion(req, line)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case latestVersion == nil && incompatibility != "":
+		trace.Add("latest_version", models.DecisionStepFailed, "no published version is compatible with the client, highest skipped: "+incompatibility)
+	case latestVersion == nil:
+		trace.Add("latest_version", models.DecisionStepFailed, "no published version in the channel")
+	case incompatibility != "":
+		trace.Add("latest_version", models.DecisionStepPassed,
+			fmt.Sprintf("highest compatible published version is %s, higher versions were skipped: %s", latestVersion.Version, incompatibility))
+	default:
+		trace.Add("latest_version", models.DecisionStepPassed, fmt.Sprintf("highest published version is %s", latestVersion.Version))
 	}
 
 	// Check if update is needed
-	hasUpdate := s.isUpdateNeeded(req.CurrentVersion, latestVersion.Version)
-	if !hasUpdate {
-		return &models.CheckUpdateResponse{
-			HasUpdate: false,
-		}, nil
+	if latestVersion == nil || !s.isUpdateNeeded(req.CurrentVersion, latestVersion.Version) {
+		if latestVersion != nil {
+			trace.Add("version_check", models.DecisionStepFailed,
+				fmt.Sprintf("%s is not newer than the current version %s", latestVersion.Version, req.CurrentVersion))
+		}
+		switch {
+		case incompatibility != "":
+			// Newer versions exist, but the client can't run them
+			return noUpdate(models.NoUpdateReasonIncompatible, incompatibility), nil
+		case latestVersion == nil:
+			// No published version the client can run
+			return noUpdate(models.NoUpdateReasonNoRelease, "no compatible published version is available"), nil
+		}
+		return noUpdate(models.NoUpdateReasonUpToDate, fmt.Sprintf("%s is the latest version", latestVersion.Version)), nil
 	}
+	trace.Add("version_check", models.DecisionStepPassed,
+		fmt.Sprintf("%s is newer than the current version %s", latestVersion.Version, req.CurrentVersion))
 
 	// Check if client meets minimum upgrade requirements
 	if latestVersion.MinUpgradeVersion != "" {
 		if !s.meetsMinimumVersion(req.CurrentVersion, latestVersion.MinUpgradeVersion) {
-			return &models.CheckUpdateResponse{
-				HasUpdate: false,
-			}, nil
+			detail := fmt.Sprintf("%s requires at least version %s", latestVersion.Version, latestVersion.MinUpgradeVersion)
+			trace.Add("min_version", models.DecisionStepFailed, detail)
+			return noUpdate(models.NoUpdateReasonBelowMinimum, detail), nil
 		}
+		trace.Add("min_version", models.DecisionStepPassed,
+			fmt.Sprintf("%s meets the minimum upgrade version %s", req.CurrentVersion, latestVersion.MinUpgradeVersion))
+	} else {
+		trace.Add("min_version", models.DecisionStepSkipped, fmt.Sprintf("%s has no minimum upgrade version", latestVersion.Version))
 	}
 
 	// Check rollout rules
-	if !s.shouldReceiveUpdate(req, req.AppID, req.Channel) {
-		return &models.CheckUpdateResponse{
-			HasUpdate: false,
-		}, nil
+	included, rollout := s.shouldReceiveUpdate(req, req.AppID, req.Channel)
+	if !included {
+		trace.Add("rollout", models.DecisionStepFailed, rollout)
+		return noUpdate(models.NoUpdateReasonExcludedRollout, fmt.Sprintf("%s is not rolled out to this client yet", latestVersion.Version)), nil
+	}
+	trace.Add("rollout", models.DecisionStepPassed, rollout)
+	if trace != nil {
+		trace.Add("rules", models.DecisionStepSkipped, s.describeUpdateRules())
+	}
+
+	// Prefer the build matching the client's platform when the version has one
+	artifact := latestVersion.FindArtifact(req.OS, req.Arch)
+	switch {
+	case artifact != nil:
+		trace.Add("platform_build", models.DecisionStepPassed, fmt.Sprintf("offering the %s/%s build", artifact.OS, artifact.Arch))
+	case requirePlatformBuild && req.OS != "" && len(latestVersion.Artifacts) > 0:
+		detail := fmt.Sprintf("%s has no build for %s/%s", latestVersion.Version, req.OS, req.Arch)
+		trace.Add("platform_build", models.DecisionStepFailed, detail)
+		return noUpdate(models.NoUpdateReasonNoPlatformBuild, detail), nil
+	case len(latestVersion.Artifacts) > 0:
+		trace.Add("platform_build", models.DecisionStepPassed,
+			fmt.Sprintf("no build for %s/%s, offering the default download", req.OS, req.Arch))
+	default:
+		trace.Add("platform_build", models.DecisionStepSkipped, fmt.Sprintf("%s has no platform builds", latestVersion.Version))
+	}
+
+	// Pick the release texts matching the client's language
+	applied, err := s.versionSvc.LocalizeVersions([]*models.Version{latestVersion}, req.Locale)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build response with update information
@@ -114,9 +298,136 @@ func (s *UpdateService) CheckUpdate(req *models.CheckUpdateRequest, clientIP str
 		Description:       latestVersion.Description,
 		ReleaseNotes:      latestVersion.ReleaseNotes,
 		MinUpgradeVersion: latestVersion.MinUpgradeVersion,
+		Locale:            applied[latestVersion.ID],
+	}
+	if line != nil {
+		response.ReleaseLine = line.Name
 	}
 
-	return response, nil
+	if artifact != nil {
+		response.DownloadURL = s.buildDownloadURL(artifact.FileURL, req)
+		response.FileSize = artifact.FileSize
+		response.FileChecksum = artifact.FileChecksum
+	}
+
+	if req.IncludeChangelog {
+		changelog, err := s.buildChangelog(req, latestVersion)
+		if err != nil {
+			return nil, err
+		}
+		response.Changelog = changelog
+	}
+
+	return &models.UpdateDecision{Response: response}, nil
+}
+
+// getSupportStatus returns the support status of an app version
+func (s *UpdateService) getSupportStatus(appID, versionNumber string) *models.SupportStatus {
+	version, err := s.versionSvc.GetVersionByNumber(appID, versionNumber)
+	if err != nil {
+		return &models.SupportStatus{Status: models.SupportStatusUnknown}
+	}
+
+	return &models.SupportStatus{
+		Status:       version.SupportStatus(time.Now()),
+		DeprecatedAt: version.DeprecatedAt,
+		EOLAt:        version.EOLAt,
+	}
+}
+
+// selectCompatibleVersion returns the highest published version for the app and channel
+// whose compatibility constraints are met by the client, or nil if there is none.
+// When a release line is given, only versions within the line are considered.
+// If versions newer than the client's were skipped as incompatible, the reason for the
+// highest one is returned.
+func (s *UpdateService) selectCompatibleVersion(req *models.CheckUpdateRequest, line *models.ReleaseLine) (*models.Version, string, error) {
+	versions, err := s.versionSvc.GetPublishedVersionsForApp(req.AppID, req.Channel)
+	if err != nil {
+		return nil, "", err
+	}
+
+	incompatibility := ""
+	for _, version := range versions {
+		if line != nil && !s.lineSvc.Contains(line, version.Version) {
+			continue
+		}
+		ok, reason := s.isCompatible(req, version)
+		if ok {
+			selected, err := s.versionSvc.GetVersionByID(version.ID)
+			if err != nil {
+				return nil, "", err
+			}
+			return selected, incompatibility, nil
+		}
+		if incompatibility == "" && s.isUpdateNeeded(req.CurrentVersion, version.Version) {
+			incompatibility = fmt.Sprintf("%s: %s", version.Version, reason)
+		}
+	}
+
+	return nil, incompatibility, nil
+}
+
+// isCompatible checks the compatibility constraints of a version against the client.
+// An unreported OS version is treated as compatible so that older clients keep updating,
+// while required dependencies and capabilities must be reported explicitly.
+// Returns false and the reason when the client is not compatible.
+func (s *UpdateService) isCompatible(req *models.CheckUpdateRequest, version *models.Version) (bool, string) {
+	if req.OSVersion != "" {
+		if version.MinOSVersion != "" && s.versionCmp.CompareVersions(req.OSVersion, version.MinOSVersion) < 0 {
+			return false, fmt.Sprintf("os version %s is below minimum %s", req.OSVersion, version.MinOSVersion)
+		}
+		if version.MaxOSVersion != "" && s.versionCmp.CompareVersions(req.OSVersion, version.MaxOSVersion) > 0 {
+			return false, fmt.Sprintf("os version %s is above maximum %s", req.OSVersion, version.MaxOSVersion)
+		}
+	}
+
+	for _, dep := range version.RequiredDependencies {
+		installed, ok := req.Dependencies[dep.Name]
+		if !ok || installed == "" {
+			return false, fmt.Sprintf("required dependency %s is not installed", dep.Name)
+		}
+		satisfied, err := s.versionCmp.SatisfiesConstraint(installed, dep.VersionRange)
+		if err != nil || !satisfied {
+			return false, fmt.Sprintf("dependency %s %s does not satisfy %s", dep.Name, installed, dep.VersionRange)
+		}
+	}
+
+	for _, capability := range version.RequiredCapabilities {
+		if !models.StringList(req.Capabilities).Contains(capability) {
+			return false, fmt.Sprintf("required capability %s is missing", capability)
+		}
+	}
+
+	return true, ""
+}
+
+// buildChangelog collects the release information of every published version between
+// the client's current version and the offered version, oldest first
+func (s *UpdateService) buildChangelog(req *models.CheckUpdateRequest, target *models.Version) ([]models.ChangelogEntry, error) {
+	versions, err := s.versionSvc.GetVersionsBetween(req.AppID, req.Channel, req.CurrentVersion, target.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := s.versionSvc.LocalizeVersions(versions, req.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	changelog := make([]models.ChangelogEntry, 0, len(versions))
+	for _, version := range versions {
+		changelog = append(changelog, models.ChangelogEntry{
+			Version:         version.Version,
+			Title:           version.Title,
+			ReleaseNotes:    version.ReleaseNotes,
+			BreakingChanges: version.BreakingChanges,
+			IsForced:        version.IsForced,
+			PublishTime:     version.PublishTime,
+			Locale:          applied[version.ID],
+		})
+	}
+
+	return changelog, nil
 }
 
 // isUpdateNeeded checks if an update is needed based on semantic version comparison
@@ -129,28 +440,39 @@ func (s *UpdateService) meetsMinimumVersion(currentVersion, minVersion string) b
 	return s.versionCmp.MeetsMinimumVersion(currentVersion, minVersion)
 }
 
-// shouldReceiveUpdate checks if the client should receive the update based on rollout rules
-func (s *UpdateService) shouldReceiveUpdate(req *models.CheckUpdateRequest, appID, channelName string) bool {
+// shouldReceiveUpdate checks if the client should receive the update based on rollout rules.
+// Returns the rollout bucket of the client as explanation.
+func (s *UpdateService) shouldReceiveUpdate(req *models.CheckUpdateRequest, appID, channelName string) (bool, string) {
 	// Get application-channel configuration for rollout percentage
 	var appChannel models.ApplicationChannel
 	if err := s.channelSvc.db.Where("app_id = ? AND channel_name = ?", appID, channelName).First(&appChannel).Error; err != nil {
 		// If no specific configuration found, allow the update
-		return true
+		return true, "the channel has no rollout configuration"
 	}
 
 	// Check rollout percentage
 	if appChannel.RolloutPercentage < 100 {
 		// Simple hash-based rollout - use client ID to determine eligibility
 		// This ensures consistent behavior for the same client
-		hash := s.hashString(req.ClientID)
-		if hash%100 >= appChannel.RolloutPercentage {
-			return false
+		bucket := s.hashString(req.ClientID) % 100
+		if bucket >= appChannel.RolloutPercentage {
+			return false, fmt.Sprintf("client is in rollout bucket %d, the rollout covers buckets below %d", bucket, appChannel.RolloutPercentage)
 		}
+		return true, fmt.Sprintf("client is in rollout bucket %d, the rollout covers buckets below %d", bucket, appChannel.RolloutPercentage)
 	}
 
 	// You can add more complex rollout rules here based on region, client version, etc.
 
-	return true
+	return true, "the channel is rolled out to all clients"
+}
+
+// describeUpdateRules explains that update rules don't take part in update checks
+func (s *UpdateService) describeUpdateRules() string {
+	var count int64
+	if err := s.db.Model(&models.UpdateRule{}).Where("enabled = ?", true).Count(&count).Error; err != nil {
+		return "update rules are not evaluated by update checks"
+	}
+	return fmt.Sprintf("update rules are not evaluated by update checks (%d enabled)", count)
 }
 
 // buildDownloadURL builds the download URL based on client requirements
@@ -166,6 +488,11 @@ func (s *UpdateService) buildDownloadURL(baseURL string, req *models.CheckUpdate
 
 // hashString creates a simple hash from a string for rollout calculations
 func (s *UpdateService) hashString(str string) int {
+	return rolloutHash(str)
+}
+
+// rolloutHash creates a simple hash from a string for rollout calculations
+func rolloutHash(str string) int {
 	hash := 0
 	for _, c := range str {
 		hash = 31*hash + int(c)
@@ -177,8 +504,9 @@ func (s *UpdateService) hashString(str string) int {
 }
 
 // RecordDownloadStart records when a download starts
-func (s *UpdateService) RecordDownloadStart(version, clientID string, clientIP string) error {
+func (s *UpdateService) RecordDownloadStart(appID, version, clientID string, clientIP string) error {
 	statReq := &models.UpdateStatRequest{
+		AppID:    appID,
 		Version:  version,
 		ClientID: clientID,
 		Action:   "download",
@@ -188,18 +516,61 @@ func (s *UpdateService) RecordDownloadStart(version, clientID string, clientIP s
 }
 
 // RecordInstallResult records the result of an installation
-func (s *UpdateService) RecordInstallResult(version, clientID string, success bool, errorMessage string, clientIP string) error {
+func (s *UpdateService) RecordInstallResult(appID, version, clientID string, success bool, errorMessage string, clientIP string) error {
 	action := "success"
 	if !success {
 		action = "failed"
 	}
 
 	statReq := &models.UpdateStatRequest{
+		AppID:        appID,
 		Version:      version,
 		ClientID:     clientID,
 		Action:       action,
 		ErrorMessage: errorMessage,
 	}
 
+	if err := s.clientSvc.RecordInstall(appID, clientID, version, success, errorMessage, time.Now().UTC(), clientIP); err != nil {
+		log.Printf("Failed to update client inventory: %v", err)
+	}
+	metrics.InstallResults.WithLabelValues(appID, action).Inc()
+
 	return s.statsSvc.RecordUpdateStat(statReq, clientIP)
 }
+
+// RecordTelemetryBatch records a batch of download and install events. Install events
+// that were not recorded before also update the client inventory.
+func (s *UpdateService) RecordTelemetryBatch(appID string, req *models.TelemetryBatchRequest, clientIP string) (*models.TelemetryBatchResponse, error) {
+	response, err := s.statsSvc.RecordTelemetryBatch(appID, req, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	for i, event := range req.Events {
+		if event.Type != models.TelemetryEventInstall || response.Results[i].Status != models.TelemetryEventAccepted {
+			continue
+		}
+
+		clientID := event.ClientID
+		if clientID == "" {
+			clientID = req.ClientID
+		}
+		at := now
+		if event.OccurredAt != nil && event.OccurredAt.Before(now) {
+			at = event.OccurredAt.UTC()
+		}
+
+		if err := s.clientSvc.RecordInstall(appID, clientID, event.Version, event.Success, event.ErrorMessage, at, clientIP); err != nil {
+			log.Printf("Failed to update client inventory: %v", err)
+		}
+
+		result := "success"
+		if !event.Success {
+			result = "failed"
+		}
+		metrics.InstallResults.WithLabelValues(appID, result).Inc()
+	}
+
+	return response, nil
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Run-Panel/VerTree/internal/config"
+	"github.com/Run-Panel/VerTree/internal/database"
+	"github.com/Run-Panel/VerTree/internal/models"
+)
+
+var testAppID string
+
+// TestMain runs the tests against a SQLite database in a temporary directory, seeded with
+// the versions and release lines of a single application
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	migrations, err := filepath.Abs("../../migrations")
+	if err != nil {
+		log.Fatal(err)
+	}
+	dir, err := os.MkdirTemp("", "vertree-services-test-")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The database and migrations are relative to the working directory
+	if err := os.Symlink(migrations, filepath.Join(dir, "migrations")); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{Driver: "sqlite", Name: "vertree"},
+		App:      config.AppConfig{Environment: "production", JWTSecret: "test-secret", TUFKeySecret: "test-secret"},
+	}
+	if err := database.Initialize(cfg); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer database.Close()
+	SetTUFKeySecret(cfg.App.TUFKeySecret)
+	if err := database.SeedDefaultData(); err != nil {
+		log.Fatalf("Failed to seed default data: %v", err)
+	}
+
+	if err := seedVersions(); err != nil {
+		log.Fatalf("Failed to seed test data: %v", err)
+	}
+
+	return m.Run()
+}
+
+// seedVersions creates an application with published versions in the stable channel:
+//
+//	1.0.0  end of life
+//	1.2.0
+//	1.10.0 published before 1.9.0
+//	1.9.0
+//	2.0.0  OS 11.0.0 to 14.0.0
+//	2.1.0  OS 11.0.0 to 14.0.0, agent >=2.0.0
+//	2.2.0  OS 11.0.0 to 14.0.0, agent >=2.0.0, capability gpu
+//
+// and the release lines 1.x, legacy (pinning clients below 1.1.0 to 1.2.x) and 3.x
+func seedVersions() error {
+	app, err := NewApplicationService().CreateApplication(&models.ApplicationRequest{Name: "update-test", IsActive: true}, 1)
+	if err != nil {
+		return err
+	}
+	testAppID = app.AppID
+
+	eol := time.Now().UTC().Add(-24 * time.Hour)
+	agent := models.DependencyList{{Name: "agent", VersionRange: ">=2.0.0"}}
+	versions := []models.VersionRequest{
+		{Version: "1.0.0", EOLAt: &eol},
+		{Version: "1.2.0"},
+		{Version: "1.10.0"},
+		{Version: "1.9.0"},
+		{Version: "2.0.0", MinOSVersion: "11.0.0", MaxOSVersion: "14.0.0"},
+		{Version: "2.1.0", MinOSVersion: "11.0.0", MaxOSVersion: "14.0.0", RequiredDependencies: agent},
+		{Version: "2.2.0", MinOSVersion: "11.0.0", MaxOSVersion: "14.0.0", RequiredDependencies: agent, RequiredCapabilities: models.StringList{"gpu"}},
+	}
+
+	versionService := NewVersionService()
+	for _, req := range versions {
+		req.AppID = testAppID
+		req.Channel = "stable"
+		req.Title = "Release " + req.Version
+		req.FileURL = "https://downloads.example.com/update-test-" + req.Version + ".zip"
+		req.FileSize = 1024
+		req.FileChecksum = "sha256:" + strings.Repeat("0", 64)
+
+		version, err := versionService.CreateVersion(&req)
+		if err != nil {
+			return err
+		}
+		if _, err := versionService.PublishVersion(version.ID); err != nil {
+			return err
+		}
+	}
+
+	lines := []models.ReleaseLineRequest{
+		{Name: "1.x", VersionRange: ">=1.0.0, <2.0.0", IsActive: true},
+		{Name: "legacy", VersionRange: "~1.2", ClientVersionRange: "<1.1.0", IsActive: true},
+		{Name: "3.x", VersionRange: ">=3.0.0", IsActive: true},
+	}
+	for _, req := range lines {
+		if _, err := NewReleaseLineService().CreateReleaseLine(testAppID, &req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestUpdateService_SelectCompatibleVersion(t *testing.T) {
+	s := NewUpdateService()
+
+	tests := []struct {
+		name             string
+		currentVersion   string
+		osVersion        string
+		dependencies     map[string]string
+		capabilities     []string
+		line             string
+		wantVersion      string
+		wantIncompatible string
+	}{
+		{"All constraints met", "1.5.0", "12.0.0", map[string]string{"agent": "2.1.0"}, []string{"gpu"}, "", "2.2.0", ""},
+		{"Unreported OS version is compatible", "1.5.0", "", map[string]string{"agent": "2.1.0"}, []string{"gpu"}, "", "2.2.0", ""},
+		{"OS version at the bounds", "1.5.0", "14.0.0", map[string]string{"agent": "2.0.0"}, []string{"gpu"}, "", "2.2.0", ""},
+		{"Missing capability", "1.5.0", "12.0.0", map[string]string{"agent": "2.1.0"}, nil, "", "2.1.0",
+			"2.2.0: required capability gpu is missing"},
+		{"Dependency not installed", "1.5.0", "12.0.0", nil, []string{"gpu"}, "", "2.0.0",
+			"2.2.0: required dependency agent is not installed"},
+		{"Dependency too old", "1.5.0", "12.0.0", map[string]string{"agent": "1.9.0"}, []string{"gpu"}, "", "2.0.0",
+			"2.2.0: dependency agent 1.9.0 does not satisfy >=2.0.0"},
+		{"OS version below minimum", "1.5.0", "10.0.0", map[string]string{"agent": "2.1.0"}, []string{"gpu"}, "", "1.10.0",
+			"2.2.0: os version 10.0.0 is below minimum 11.0.0"},
+		{"OS version above maximum", "1.5.0", "15.0.0", map[string]string{"agent": "2.1.0"}, []string{"gpu"}, "", "1.10.0",
+			"2.2.0: os version 15.0.0 is above maximum 14.0.0"},
+		{"Skipped versions not newer than the client are not reported", "2.2.0", "10.0.0", nil, nil, "", "1.10.0", ""},
+
+		// Release lines
+		{"Pinned to a release line", "1.0.0", "12.0.0", map[string]string{"agent": "2.1.0"}, []string{"gpu"}, "1.x", "1.10.0", ""},
+		{"Pinned to a patch line", "1.0.0", "", nil, nil, "legacy", "1.2.0", ""},
+		{"Release line without published versions", "1.0.0", "", nil, nil, "3.x", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &models.CheckUpdateRequest{
+				AppID:          testAppID,
+				CurrentVersion: tt.currentVersion,
+				Channel:        "stable",
+				ClientID:       "update-test-client",
+				OSVersion:      tt.osVersion,
+				Dependencies:   tt.dependencies,
+				Capabilities:   tt.capabilities,
+			}
+
+			var line *models.ReleaseLine
+			if tt.line != "" {
+				var err error
+				if line, err = s.lineSvc.GetReleaseLine(testAppID, tt.line); err != nil {
+					t.Fatalf("GetReleaseLine(%q) error = %v", tt.line, err)
+				}
+			}
+
+			version, incompatibility, err := s.selectCompatibleVersion(req, line)
+			if err != nil {
+				t.Fatalf("selectCompatibleVersion() error = %v", err)
+			}
+
+			got := ""
+			if version != nil {
+				got = version.Version
+			}
+			if got != tt.wantVersion {
+				t.Errorf("selectCompatibleVersion() version = %q, expected %q", got, tt.wantVersion)
+			}
+			if incompatibility != tt.wantIncompatible {
+				t.Errorf("selectCompatibleVersion() incompatibility = %q, expected %q", incompatibility, tt.wantIncompatible)
+			}
+		})
+	}
+}
+
+func TestUpdateService_IsCompatible(t *testing.T) {
+	s := NewUpdateService()
+
+	version := &models.Version{
+		Version:              "3.0.0",
+		MinOSVersion:         "10.15.0",
+		MaxOSVersion:         "14.0.0",
+		RequiredDependencies: models.DependencyList{{Name: "agent", VersionRange: "^2.1.0"}},
+		RequiredCapabilities: models.StringList{"gpu", "tpm"},
+	}
+
+	tests := []struct {
+		name         string
+		osVersion    string
+		dependencies map[string]string
+		capabilities []string
+		expected     bool
+		reason       string
+	}{
+		{"All constraints met", "13.1.0", map[string]string{"agent": "2.3.0"}, []string{"tpm", "gpu"}, true, ""},
+		{"OS version compared as semver", "10.9.0", map[string]string{"agent": "2.3.0"}, []string{"gpu", "tpm"}, false,
+			"os version 10.9.0 is below minimum 10.15.0"},
+		{"OS version above maximum", "14.0.1", map[string]string{"agent": "2.3.0"}, []string{"gpu", "tpm"}, false,
+			"os version 14.0.1 is above maximum 14.0.0"},
+		{"Dependency with empty version", "13.1.0", map[string]string{"agent": ""}, []string{"gpu", "tpm"}, false,
+			"required dependency agent is not installed"},
+		{"Dependency outside the range", "13.1.0", map[string]string{"agent": "3.0.0"}, []string{"gpu", "tpm"}, false,
+			"dependency agent 3.0.0 does not satisfy ^2.1.0"},
+		{"Invalid dependency version", "13.1.0", map[string]string{"agent": "latest"}, []string{"gpu", "tpm"}, false,
+			"dependency agent latest does not satisfy ^2.1.0"},
+		{"One capability missing", "13.1.0", map[string]string{"agent": "2.3.0"}, []string{"gpu"}, false,
+			"required capability tpm is missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &models.CheckUpdateRequest{
+				OSVersion:    tt.osVersion,
+				Dependencies: tt.dependencies,
+				Capabilities: tt.capabilities,
+			}
+			ok, reason := s.isCompatible(req, version)
+			if ok != tt.expected || reason != tt.reason {
+				t.Errorf("isCompatible() = %v, %q, expected %v, %q", ok, reason, tt.expected, tt.reason)
+			}
+		})
+	}
+}
+
+func TestUpdateService_ExplainUpdate(t *testing.T) {
+	s := NewUpdateService()
+
+	tests := []struct {
+		name          string
+		req           models.CheckUpdateRequest
+		outcome       string
+		reason        string
+		latestVersion string
+		supportStatus string
+		steps         map[string]string // Step -> status
+		details       map[string]string // Step -> detail substring
+	}{
+		{
+			name:          "End of life version pinned to a release line",
+			req:           models.CheckUpdateRequest{CurrentVersion: "1.0.0"},
+			outcome:       models.DecisionOutcomeUpdate,
+			latestVersion: "1.2.0",
+			supportStatus: models.SupportStatusEOL,
+			steps: map[string]string{
+				"application":    models.DecisionStepPassed,
+				"channel":        models.DecisionStepPassed,
+				"release_line":   models.DecisionStepPassed,
+				"latest_version": models.DecisionStepPassed,
+				"version_check":  models.DecisionStepPassed,
+				"min_version":    models.DecisionStepSkipped,
+				"rollout":        models.DecisionStepPassed,
+			},
+			details: map[string]string{
+				"release_line":   "release line legacy (~1.2)",
+				"latest_version": "highest published version is 1.2.0",
+			},
+		},
+		{
+			name:          "Newer versions are incompatible",
+			req:           models.CheckUpdateRequest{CurrentVersion: "1.10.0", OSVersion: "10.0.0"},
+			outcome:       models.DecisionOutcomeNoUpdate,
+			reason:        models.NoUpdateReasonIncompatible,
+			supportStatus: models.SupportStatusSupported,
+			steps: map[string]string{
+				"release_line":   models.DecisionStepSkipped,
+				"latest_version": models.DecisionStepPassed,
+				"version_check":  models.DecisionStepFailed,
+			},
+			details: map[string]string{
+				"latest_version": "highest compatible published version is 1.10.0, higher versions were skipped: 2.2.0: os version 10.0.0 is below minimum 11.0.0",
+				"version_check":  "1.10.0 is not newer than the current version 1.10.0",
+			},
+		},
+		{
+			name:          "Release line without published versions",
+			req:           models.CheckUpdateRequest{CurrentVersion: "1.2.0", ReleaseLine: "3.x"},
+			outcome:       models.DecisionOutcomeNoUpdate,
+			reason:        models.NoUpdateReasonNoRelease,
+			supportStatus: models.SupportStatusSupported,
+			steps: map[string]string{
+				"release_line":   models.DecisionStepPassed,
+				"latest_version": models.DecisionStepFailed,
+			},
+		},
+		{
+			name:    "Unknown release line",
+			req:     models.CheckUpdateRequest{CurrentVersion: "1.2.0", ReleaseLine: "0.x"},
+			outcome: models.DecisionOutcomeError,
+			steps: map[string]string{
+				"release_line": models.DecisionStepFailed,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &models.ExplainUpdateRequest{CheckUpdateRequest: tt.req}
+			req.Channel = "stable"
+
+			response, err := s.ExplainUpdate(testAppID, req)
+			if err != nil {
+				t.Fatalf("ExplainUpdate() error = %v", err)
+			}
+
+			if response.Outcome != tt.outcome || response.Reason != tt.reason {
+				t.Errorf("ExplainUpdate() outcome = %q, reason %q, expected %q, reason %q",
+					response.Outcome, response.Reason, tt.outcome, tt.reason)
+			}
+			if tt.latestVersion != "" && (response.Response == nil || response.Response.LatestVersion != tt.latestVersion) {
+				t.Errorf("ExplainUpdate() response = %+v, expected an update to %s", response.Response, tt.latestVersion)
+			}
+			if tt.supportStatus != "" && (response.Response == nil || response.Response.SupportStatus.Status != tt.supportStatus) {
+				t.Errorf("ExplainUpdate() response = %+v, expected support status %s", response.Response, tt.supportStatus)
+			}
+
+			steps := map[string]models.DecisionStep{}
+			for _, step := range response.Steps {
+				steps[step.Step] = step
+			}
+			for name, status := range tt.steps {
+				if step, ok := steps[name]; !ok || step.Status != status {
+					t.Errorf("ExplainUpdate() step %s = %+v, expected status %s", name, step, status)
+				}
+			}
+			for name, detail := range tt.details {
+				if !strings.Contains(steps[name].Detail, detail) {
+					t.Errorf("ExplainUpdate() step %s detail = %q, expected it to contain %q", name, steps[name].Detail, detail)
+				}
+			}
+		})
+	}
+}
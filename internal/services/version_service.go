@@ -2,10 +2,14 @@ package services
 
 import (
 	"fmt"
+	"log"
+	"sort"
 	"time"
 
 	"github.com/Run-Panel/VerTree/internal/database"
+	"github.com/Run-Panel/VerTree/internal/i18n"
 	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/utils"
 	"gorm.io/gorm"
 )
 
@@ -13,6 +17,9 @@ import (
 type VersionService struct {
 	db             *gorm.DB
 	channelService *ChannelService
+	versionCmp     *utils.VersionComparer
+	tufService     *TUFService
+	eventService   *EventService
 }
 
 // NewVersionService creates a new version service instance
@@ -20,6 +27,17 @@ func NewVersionService() *VersionService {
 	return &VersionService{
 		db:             database.DB,
 		channelService: NewChannelService(),
+		versionCmp:     utils.NewVersionComparer(),
+		tufService:     NewTUFService(),
+		eventService:   NewEventService(),
+	}
+}
+
+// refreshTUFMetadata re-signs the TUF metadata of an app after its published downloads changed.
+// Failures are only logged: the change itself is saved and metadata is retried on the next change.
+func (s *VersionService) refreshTUFMetadata(appID string) {
+	if err := s.tufService.Regenerate(appID); err != nil {
+		log.Printf("Failed to regenerate TUF metadata for %s: %v", appID, err)
 	}
 }
 
@@ -36,6 +54,10 @@ func (s *VersionService) CreateVersion(req *models.VersionRequest) (*models.Vers
 		return nil, err
 	}
 
+	if err := s.validateConstraints(req); err != nil {
+		return nil, err
+	}
+
 	version := &models.Version{
 		AppID:             req.AppID,
 		Version:           req.Version,
@@ -50,6 +72,14 @@ func (s *VersionService) CreateVersion(req *models.VersionRequest) (*models.Vers
 		FileChecksum:      req.FileChecksum,
 		IsForced:          req.IsForced,
 		IsPublished:       false,
+
+		MinOSVersion:         req.MinOSVersion,
+		MaxOSVersion:         req.MaxOSVersion,
+		RequiredDependencies: req.RequiredDependencies,
+		RequiredCapabilities: req.RequiredCapabilities,
+
+		DeprecatedAt: req.DeprecatedAt,
+		EOLAt:        req.EOLAt,
 	}
 
 	if err := s.db.Create(version).Error; err != nil {
@@ -72,10 +102,47 @@ func (s *VersionService) CreateVersion(req *models.VersionRequest) (*models.Vers
 	return version, nil
 }
 
+// validateConstraints validates the compatibility constraints of a version request
+func (s *VersionService) validateConstraints(req *models.VersionRequest) error {
+	if req.MinOSVersion != "" && !s.versionCmp.IsValidSemVer(req.MinOSVersion) {
+		return fmt.Errorf("invalid min_os_version: %s", req.MinOSVersion)
+	}
+	if req.MaxOSVersion != "" && !s.versionCmp.IsValidSemVer(req.MaxOSVersion) {
+		return fmt.Errorf("invalid max_os_version: %s", req.MaxOSVersion)
+	}
+	if req.MinOSVersion != "" && req.MaxOSVersion != "" &&
+		s.versionCmp.CompareVersions(req.MinOSVersion, req.MaxOSVersion) > 0 {
+		return fmt.Errorf("min_os_version must not be greater than max_os_version")
+	}
+
+	if err := validateSupportWindow(req.DeprecatedAt, req.EOLAt); err != nil {
+		return err
+	}
+
+	for _, dep := range req.RequiredDependencies {
+		if dep.Name == "" {
+			return fmt.Errorf("required dependency name cannot be empty")
+		}
+		if !s.versionCmp.IsValidConstraint(dep.VersionRange) {
+			return fmt.Errorf("invalid version range for dependency %s: %s", dep.Name, dep.VersionRange)
+		}
+	}
+
+	return nil
+}
+
+// validateSupportWindow checks that a version is not deprecated after its end of life
+func validateSupportWindow(deprecatedAt, eolAt *time.Time) error {
+	if deprecatedAt != nil && eolAt != nil && deprecatedAt.After(*eolAt) {
+		return fmt.Errorf("deprecated_at must not be later than eol_at")
+	}
+	return nil
+}
+
 // GetVersionByID retrieves a version by ID
 func (s *VersionService) GetVersionByID(id uint) (*models.Version, error) {
 	var version models.Version
-	if err := s.db.First(&version, id).Error; err != nil {
+	if err := s.db.Preload("Artifacts").First(&version, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("version not found")
 		}
@@ -109,6 +176,10 @@ func (s *VersionService) UpdateVersion(id uint, req *models.VersionRequest) (*mo
 		return nil, err
 	}
 
+	if err := s.validateConstraints(req); err != nil {
+		return nil, err
+	}
+
 	// Update fields
 	version.Version = req.Version
 	version.Channel = req.Channel
@@ -121,6 +192,12 @@ func (s *VersionService) UpdateVersion(id uint, req *models.VersionRequest) (*mo
 	version.FileSize = req.FileSize
 	version.FileChecksum = req.FileChecksum
 	version.IsForced = req.IsForced
+	version.MinOSVersion = req.MinOSVersion
+	version.MaxOSVersion = req.MaxOSVersion
+	version.RequiredDependencies = req.RequiredDependencies
+	version.RequiredCapabilities = req.RequiredCapabilities
+	version.DeprecatedAt = req.DeprecatedAt
+	version.EOLAt = req.EOLAt
 
 	if err := s.db.Save(version).Error; err != nil {
 		return nil, fmt.Errorf("failed to update version: %w", err)
@@ -166,9 +243,85 @@ func (s *VersionService) PublishVersion(id uint) (*models.Version, error) {
 		return nil, fmt.Errorf("failed to publish version: %w", err)
 	}
 
+	s.refreshTUFMetadata(version.AppID)
+	s.eventService.Publish(&models.ReleaseEvent{
+		Type:     models.ReleaseEventVersionPublished,
+		AppID:    version.AppID,
+		Channel:  version.Channel,
+		Version:  version.Version,
+		Title:    version.Title,
+		IsForced: version.IsForced,
+	})
+
+	return version, nil
+}
+
+// ForceUpdate marks a published version as a forced update and notifies subscribed clients
+func (s *VersionService) ForceUpdate(id uint, req *models.ForceUpdateRequest) (*models.Version, error) {
+	version, err := s.GetVersionByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !version.IsPublished {
+		return nil, fmt.Errorf("only published versions can be forced")
+	}
+
+	if !version.IsForced {
+		version.IsForced = true
+		if err := s.db.Save(version).Error; err != nil {
+			return nil, fmt.Errorf("failed to force update: %w", err)
+		}
+		s.refreshTUFMetadata(version.AppID)
+	}
+
+	s.eventService.Publish(&models.ReleaseEvent{
+		Type:     models.ReleaseEventForcedUpdate,
+		AppID:    version.AppID,
+		Channel:  version.Channel,
+		Version:  version.Version,
+		Title:    version.Title,
+		IsForced: true,
+		Message:  req.Message,
+	})
+
 	return version, nil
 }
 
+// UpdateSupportWindow sets the deprecation and end-of-life dates of a version.
+// Unlike UpdateVersion this is allowed for published versions.
+func (s *VersionService) UpdateSupportWindow(id uint, req *models.SupportWindowRequest) (*models.Version, error) {
+	version, err := s.GetVersionByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateSupportWindow(req.DeprecatedAt, req.EOLAt); err != nil {
+		return nil, err
+	}
+
+	version.DeprecatedAt = req.DeprecatedAt
+	version.EOLAt = req.EOLAt
+
+	if err := s.db.Save(version).Error; err != nil {
+		return nil, fmt.Errorf("failed to update support window: %w", err)
+	}
+
+	return version, nil
+}
+
+// GetVersionByNumber retrieves a version of an app by its version number
+func (s *VersionService) GetVersionByNumber(appID, versionNumber string) (*models.Version, error) {
+	var version models.Version
+	if err := s.db.Where("app_id = ? AND version = ?", appID, versionNumber).First(&version).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("version not found")
+		}
+		return nil, fmt.Errorf("failed to get version: %w", err)
+	}
+	return &version, nil
+}
+
 // UnpublishVersion unpublishes a version
 func (s *VersionService) UnpublishVersion(id uint) (*models.Version, error) {
 	version, err := s.GetVersionByID(id)
@@ -187,6 +340,8 @@ func (s *VersionService) UnpublishVersion(id uint) (*models.Version, error) {
 		return nil, fmt.Errorf("failed to unpublish version: %w", err)
 	}
 
+	s.refreshTUFMetadata(version.AppID)
+
 	return version, nil
 }
 
@@ -268,6 +423,25 @@ func (s *VersionService) GetLatestVersionForApp(appID, channel string) (*models.
 	return &version, nil
 }
 
+// GetPublishedVersionsForApp gets the published versions for an app and channel, highest
+// version first. Only the version number and the compatibility constraints are loaded, get
+// the version chosen from them with GetVersionByID.
+func (s *VersionService) GetPublishedVersionsForApp(appID, channel string) ([]*models.Version, error) {
+	var versions []*models.Version
+	if err := s.db.Select("id", "version", "min_os_version", "max_os_version", "required_dependencies", "required_capabilities").
+		Where("app_id = ? AND channel = ? AND is_published = ?", appID, channel, true).
+		Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get published versions: %w", err)
+	}
+
+	// Versions aren't published in version order, an LTS hotfix may follow a newer major
+	sort.SliceStable(versions, func(i, j int) bool {
+		return s.versionCmp.CompareVersions(versions[i].Version, versions[j].Version) > 0
+	})
+
+	return versions, nil
+}
+
 // GetVersionsForApp gets published versions for a specific app with optional channel filter
 func (s *VersionService) GetVersionsForApp(appID, channel string, limit int, publishedOnly bool) ([]*models.Version, error) {
 	var versions []*models.Version
@@ -304,3 +478,213 @@ func (s *VersionService) GetVersionsForApp(appID, channel string, limit int, pub
 
 	return versions, nil
 }
+
+// GetVersionsBetween gets the published versions of an app and channel that are newer than
+// fromVersion and not newer than toVersion, ordered from oldest to newest
+func (s *VersionService) GetVersionsBetween(appID, channel, fromVersion, toVersion string) ([]*models.Version, error) {
+	var versions []*models.Version
+	if err := s.db.Where("app_id = ? AND channel = ? AND is_published = ?", appID, channel, true).
+		Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get versions for changelog: %w", err)
+	}
+
+	var between []*models.Version
+	for _, version := range versions {
+		if s.versionCmp.CompareVersions(version.Version, fromVersion) > 0 &&
+			s.versionCmp.CompareVersions(version.Version, toVersion) <= 0 {
+			between = append(between, version)
+		}
+	}
+
+	sort.SliceStable(between, func(i, j int) bool {
+		return s.versionCmp.CompareVersions(between[i].Version, between[j].Version) < 0
+	})
+
+	return between, nil
+}
+
+// GetLocalizations lists all localizations of a version
+func (s *VersionService) GetLocalizations(versionID uint) ([]*models.VersionLocalization, error) {
+	if _, err := s.GetVersionByID(versionID); err != nil {
+		return nil, err
+	}
+
+	var localizations []*models.VersionLocalization
+	if err := s.db.Where("version_id = ?", versionID).Order("locale").Find(&localizations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get localizations: %w", err)
+	}
+
+	return localizations, nil
+}
+
+// UpsertLocalization creates or updates the localization of a version for a locale.
+// Localizations may be edited after a version is published.
+func (s *VersionService) UpsertLocalization(versionID uint, locale string, req *models.VersionLocalizationRequest) (*models.VersionLocalization, error) {
+	if _, err := s.GetVersionByID(versionID); err != nil {
+		return nil, err
+	}
+
+	locale = i18n.NormalizeLocale(locale)
+	if locale == "" {
+		return nil, fmt.Errorf("locale is required")
+	}
+
+	if req.Title == "" && req.Description == "" && req.ReleaseNotes == "" && req.BreakingChanges == "" {
+		return nil, fmt.Errorf("at least one localized field is required")
+	}
+
+	// Include soft-deleted rows so a previously removed locale can be restored
+	var localization models.VersionLocalization
+	err := s.db.Unscoped().Where("version_id = ? AND locale = ?", versionID, locale).First(&localization).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check localization: %w", err)
+	}
+
+	localization.VersionID = versionID
+	localization.Locale = locale
+	localization.Title = req.Title
+	localization.Description = req.Description
+	localization.ReleaseNotes = req.ReleaseNotes
+	localization.BreakingChanges = req.BreakingChanges
+	localization.DeletedAt = gorm.DeletedAt{}
+
+	if err := s.db.Unscoped().Save(&localization).Error; err != nil {
+		return nil, fmt.Errorf("failed to save localization: %w", err)
+	}
+
+	return &localization, nil
+}
+
+// DeleteLocalization removes the localization of a version for a locale
+func (s *VersionService) DeleteLocalization(versionID uint, locale string) error {
+	result := s.db.Where("version_id = ? AND locale = ?", versionID, i18n.NormalizeLocale(locale)).
+		Delete(&models.VersionLocalization{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete localization: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("localization not found")
+	}
+
+	return nil
+}
+
+// UpsertArtifact creates or updates the artifact of a version for a platform (OS and architecture)
+func (s *VersionService) UpsertArtifact(versionID uint, req *models.VersionArtifactRequest) (*models.VersionArtifact, error) {
+	version, err := s.GetVersionByID(versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.OS == "" {
+		return nil, fmt.Errorf("os is required")
+	}
+	if problem := validateImportFile(req.FileURL, req.FileSize); problem != "" {
+		return nil, fmt.Errorf("%s", problem)
+	}
+	if req.FileChecksum == "" {
+		return nil, fmt.Errorf("file_checksum is required")
+	}
+
+	// Include soft-deleted rows so a previously removed platform can be restored
+	var artifact models.VersionArtifact
+	err = s.db.Unscoped().Where("version_id = ? AND os = ? AND arch = ?", versionID, req.OS, req.Arch).First(&artifact).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check artifact: %w", err)
+	}
+
+	artifact.VersionID = versionID
+	artifact.OS = req.OS
+	artifact.Arch = req.Arch
+	artifact.FileName = req.FileName
+	artifact.FileURL = req.FileURL
+	artifact.FileSize = req.FileSize
+	artifact.FileChecksum = req.FileChecksum
+	artifact.Signature = req.Signature
+	artifact.SHA512 = req.SHA512
+	artifact.SHA1 = req.SHA1
+	artifact.DeletedAt = gorm.DeletedAt{}
+
+	if err := s.db.Unscoped().Save(&artifact).Error; err != nil {
+		return nil, fmt.Errorf("failed to save artifact: %w", err)
+	}
+
+	if version.IsPublished {
+		s.refreshTUFMetadata(version.AppID)
+	}
+
+	return &artifact, nil
+}
+
+// DeleteArtifact removes an artifact of a version
+func (s *VersionService) DeleteArtifact(versionID, artifactID uint) error {
+	version, err := s.GetVersionByID(versionID)
+	if err != nil {
+		return err
+	}
+
+	result := s.db.Where("version_id = ? AND id = ?", versionID, artifactID).Delete(&models.VersionArtifact{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete artifact: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("artifact not found")
+	}
+
+	if version.IsPublished {
+		s.refreshTUFMetadata(version.AppID)
+	}
+
+	return nil
+}
+
+// LocalizeVersions overlays the best matching localization onto each version.
+// preferences is an Accept-Language style string; versions without a matching
+// localization keep their default texts. Returns the locale applied to each version ID.
+func (s *VersionService) LocalizeVersions(versions []*models.Version, preferences string) (map[uint]string, error) {
+	applied := make(map[uint]string)
+
+	locales := i18n.ParseAcceptLanguage(preferences)
+	if len(locales) == 0 || len(versions) == 0 {
+		return applied, nil
+	}
+
+	versionIDs := make([]uint, 0, len(versions))
+	for _, version := range versions {
+		versionIDs = append(versionIDs, version.ID)
+	}
+
+	var localizations []models.VersionLocalization
+	if err := s.db.Where("version_id IN ?", versionIDs).Find(&localizations).Error; err != nil {
+		return nil, fmt.Errorf("failed to load localizations: %w", err)
+	}
+
+	byVersion := make(map[uint]map[string]*models.VersionLocalization)
+	for i := range localizations {
+		l := &localizations[i]
+		if byVersion[l.VersionID] == nil {
+			byVersion[l.VersionID] = make(map[string]*models.VersionLocalization)
+		}
+		byVersion[l.VersionID][l.Locale] = l
+	}
+
+	for _, version := range versions {
+		candidates := byVersion[version.ID]
+		if len(candidates) == 0 {
+			continue
+		}
+
+		available := make([]string, 0, len(candidates))
+		for locale := range candidates {
+			available = append(available, locale)
+		}
+		sort.Strings(available)
+
+		if locale, ok := i18n.MatchLocale(locales, available); ok {
+			version.ApplyLocalization(candidates[locale])
+			applied[version.ID] = locale
+		}
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,42 @@
+package utils
+
+import "testing"
+
+func TestCanonicalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected string
+		wantErr  bool
+	}{
+		{"Sorted keys", map[string]interface{}{"b": 1, "a": 2}, `{"a":2,"b":1}`, false},
+		{"Struct fields sorted", struct {
+			Zeta  string `json:"zeta"`
+			Alpha int    `json:"alpha"`
+		}{"z", 1}, `{"alpha":1,"zeta":"z"}`, false},
+		{"Nested values", map[string]interface{}{"list": []interface{}{true, nil, "x"}, "obj": map[string]interface{}{"k": "v"}}, `{"list":[true,null,"x"],"obj":{"k":"v"}}`, false},
+		{"No HTML escaping", map[string]string{"url": "https://x.example.com/?a=1&b=<2>"}, `{"url":"https://x.example.com/?a=1&b=<2>"}`, false},
+		{"Quotes and backslashes escaped", "a\"b\\c", `"a\"b\\c"`, false},
+		{"Unicode kept as is", "版本", `"版本"`, false},
+		{"Large integers kept", int64(1) << 53, `9007199254740992`, false},
+		{"Floats rejected", 1.5, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CanonicalJSON(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CanonicalJSON(%v) expected error, got %s", tt.input, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CanonicalJSON(%v) unexpected error: %v", tt.input, err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("CanonicalJSON(%v) = %s, expected %s", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxErrorSignatureLength is the length in characters signatures are cut to
+const maxErrorSignatureLength = 255
+
+// errorSignatureRules replace the variable parts of error messages, in order: URLs and paths
+// before the numbers they contain, UUIDs before other hex IDs
+var errorSignatureRules = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://\S+`), "<url>"},
+	{regexp.MustCompile(`[A-Za-z]:\\[^\s"'<>|:]*`), "<path>"},
+	{regexp.MustCompile(`(^|[\s"'=(\[])(?:~|\.{1,2})?(?:/[^\s"'<>:,;()\[\]]+)+`), "${1}<path>"},
+	{regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b0[xX][0-9a-fA-F]+\b`), "<hex>"},
+}
+
+// errorSignatureHex matches hex IDs such as checksums and commit hashes, which are told
+// apart from words and plain numbers by containing both letters and digits
+var errorSignatureHex = regexp.MustCompile(`\b[0-9a-fA-F]{8,}\b`)
+
+// errorSignatureNumber matches numbers, including dotted ones like versions and IP addresses
+var errorSignatureNumber = regexp.MustCompile(`\b\d+(?:\.\d+)*\b`)
+
+// ErrorSignature normalizes an error message into a signature shared by the messages of the
+// same error: paths, URLs, UUIDs, hex IDs and numbers are replaced by placeholders and
+// whitespace is collapsed.
+func ErrorSignature(message string) string {
+	signature := message
+	for _, rule := range errorSignatureRules {
+		signature = rule.pattern.ReplaceAllString(signature, rule.replacement)
+	}
+	signature = errorSignatureHex.ReplaceAllStringFunc(signature, func(id string) string {
+		if strings.ContainsAny(id, "0123456789") && strings.IndexFunc(id, isHexLetter) >= 0 {
+			return "<hex>"
+		}
+		return id
+	})
+	signature = errorSignatureNumber.ReplaceAllString(signature, "<n>")
+	signature = strings.Join(strings.Fields(signature), " ")
+
+	if utf8.RuneCountInString(signature) > maxErrorSignatureLength {
+		signature = string([]rune(signature)[:maxErrorSignatureLength])
+	}
+	return signature
+}
+
+// isHexLetter reports whether r is a hex digit letter
+func isHexLetter(r rune) bool {
+	return (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrorSignature(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{"Unix path", "open /var/lib/app/update-1.2.3.tar.gz: permission denied", "open <path>: permission denied"},
+		{"Home path", "cannot write ~/.config/app/state.json", "cannot write <path>"},
+		{"Windows path", `rename C:\Users\ann\AppData\Local\App\app.exe: access denied`, "rename <path>: access denied"},
+		{"URL", "GET https://cdn.example.com/app/1.2.3/app.zip?sig=abc123: timeout", "GET <url> timeout"},
+		{"Numbers and versions", "expected 1048576 bytes, got 524288 for version 1.2.3", "expected <n> bytes, got <n> for version <n>"},
+		{"Hex error code", "installer exited with 0x80070005", "installer exited with <hex>"},
+		{"Checksum", "checksum mismatch: got 9f86d081884c7d659a2feaa0c55ad015", "checksum mismatch: got <hex>"},
+		{"UUID", "job 123e4567-e89b-12d3-a456-426614174000 failed", "job <uuid> failed"},
+		{"Words kept", "sha256 of deadbeef and utf8 and/or x64 differ", "sha256 of deadbeef and utf8 and/or x64 differ"},
+		{"Whitespace collapsed", "  disk \t full\n", "disk full"},
+		{"Empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ErrorSignature(tt.message); result != tt.expected {
+				t.Errorf("ErrorSignature(%q) = %q, expected %q", tt.message, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestErrorSignatureGroupsMessages(t *testing.T) {
+	a := ErrorSignature("open /tmp/vertree-3817/app.zip: no space left on device (wrote 4096 of 81920)")
+	b := ErrorSignature("open /tmp/vertree-99/app.zip: no space left on device (wrote 0 of 1024)")
+	if a != b {
+		t.Errorf("signatures differ: %q and %q", a, b)
+	}
+
+	long := ErrorSignature(strings.Repeat("x", 300))
+	if len(long) != maxErrorSignatureLength {
+		t.Errorf("long signature has length %d, expected %d", len(long), maxErrorSignatureLength)
+	}
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"path"
+	"strings"
+)
+
+// platformOSKeywords maps file name keywords to operating systems. "darwin" is
+// matched before "win" so that macOS builds are not mistaken for Windows ones.
+var platformOSKeywords = []struct {
+	keyword string
+	os      string
+}{
+	{"darwin", "darwin"},
+	{"macos", "darwin"},
+	{"osx", "darwin"},
+	{"mac", "darwin"},
+	{".dmg", "darwin"},
+	{".pkg", "darwin"},
+	{"windows", "windows"},
+	{"win", "windows"},
+	{".exe", "windows"},
+	{".msi", "windows"},
+	{".nupkg", "windows"},
+	{"linux", "linux"},
+	{".appimage", "linux"},
+	{".deb", "linux"},
+	{".rpm", "linux"},
+}
+
+// platformArchKeywords maps file name keywords to architectures
+var platformArchKeywords = []struct {
+	keyword string
+	arch    string
+}{
+	{"universal", "universal"},
+	{"aarch64", "arm64"},
+	{"arm64", "arm64"},
+	{"x86_64", "amd64"},
+	{"amd64", "amd64"},
+	{"x64", "amd64"},
+	{"i386", "386"},
+	{"i686", "386"},
+	{"386", "386"},
+	{"x86", "386"},
+	{"armv7", "arm"},
+	{"armhf", "arm"},
+}
+
+// DetectPlatform guesses the operating system and architecture of a release file from
+// its name, e.g. "app-1.2.0-darwin-arm64.zip" yields ("darwin", "arm64").
+// Empty strings are returned for the parts that can't be detected.
+func DetectPlatform(fileName string) (os, arch string) {
+	name := strings.ToLower(path.Base(fileName))
+
+	for _, k := range platformOSKeywords {
+		if strings.Contains(name, k.keyword) {
+			os = k.os
+			break
+		}
+	}
+
+	for _, k := range platformArchKeywords {
+		if strings.Contains(name, k.keyword) {
+			arch = k.arch
+			break
+		}
+	}
+
+	return os, arch
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestDetectPlatform(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		os       string
+		arch     string
+	}{
+		{"Go style darwin arm64", "app_1.2.0_darwin_arm64.tar.gz", "darwin", "arm64"},
+		{"Go style linux amd64", "app-1.2.0-linux-amd64.tar.gz", "linux", "amd64"},
+		{"Windows x64 installer", "App-Setup-1.2.0-x64.exe", "windows", "amd64"},
+		{"Windows 32-bit", "app-1.2.0-windows-386.zip", "windows", "386"},
+		{"macOS universal dmg", "App-1.2.0-universal.dmg", "darwin", "universal"},
+		{"macOS without arch", "App-1.2.0-mac.zip", "darwin", ""},
+		{"Linux aarch64 AppImage", "App-1.2.0-aarch64.AppImage", "linux", "arm64"},
+		{"Debian package", "app_1.2.0_amd64.deb", "linux", "amd64"},
+		{"Path prefix ignored", "/releases/win/app-1.2.0.tar.gz", "", ""},
+		{"Unknown", "source.tar.gz", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os, arch := DetectPlatform(tt.fileName)
+			if os != tt.os || arch != tt.arch {
+				t.Errorf("DetectPlatform(%q) = (%q, %q), want (%q, %q)",
+					tt.fileName, os, arch, tt.os, tt.arch)
+			}
+		})
+	}
+}
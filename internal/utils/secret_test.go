@@ -0,0 +1,42 @@
+package utils
+
+import "testing"
+
+func TestEncryptDecryptSecret(t *testing.T) {
+	secret := []byte("private key material")
+
+	encrypted, err := EncryptSecret(secret, "passphrase")
+	if err != nil {
+		t.Fatalf("EncryptSecret() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		encoded    string
+		passphrase string
+		wantErr    bool
+	}{
+		{"Correct passphrase", encrypted, "passphrase", false},
+		{"Wrong passphrase", encrypted, "other", true},
+		{"Not base64", "%%%", "passphrase", true},
+		{"Too short", "AAAA", "passphrase", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decrypted, err := DecryptSecret(tt.encoded, tt.passphrase)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("DecryptSecret() expected error, got %q", decrypted)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecryptSecret() unexpected error: %v", err)
+			}
+			if string(decrypted) != string(secret) {
+				t.Errorf("DecryptSecret() = %q, expected %q", decrypted, secret)
+			}
+		})
+	}
+}
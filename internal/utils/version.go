@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
@@ -108,6 +109,32 @@ func (vc *VersionComparer) CompareVersions(version1, version2 string) int {
 	return 0
 }
 
+// SatisfiesConstraint checks if a version satisfies a semantic version constraint
+// such as ">=1.2.0 <2.0.0" or "^2.1". An empty constraint is always satisfied.
+func (vc *VersionComparer) SatisfiesConstraint(version, constraint string) (bool, error) {
+	if strings.TrimSpace(constraint) == "" {
+		return true, nil
+	}
+
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false, fmt.Errorf("invalid version constraint %q: %w", constraint, err)
+	}
+
+	v, err := semver.NewVersion(vc.normalizeVersion(version))
+	if err != nil {
+		return false, fmt.Errorf("invalid version %q: %w", version, err)
+	}
+
+	return c.Check(v), nil
+}
+
+// IsValidConstraint checks if a string is a valid semantic version constraint
+func (vc *VersionComparer) IsValidConstraint(constraint string) bool {
+	_, err := semver.NewConstraint(constraint)
+	return err == nil
+}
+
 // IsValidSemVer checks if a version string is a valid semantic version
 func (vc *VersionComparer) IsValidSemVer(version string) bool {
 	if version == "" {
@@ -214,3 +214,40 @@ func TestVersionComparer_SortVersions(t *testing.T) {
 		})
 	}
 }
+
+func TestVersionComparer_SatisfiesConstraint(t *testing.T) {
+	vc := NewVersionComparer()
+
+	tests := []struct {
+		name       string
+		version    string
+		constraint string
+		expected   bool
+		wantErr    bool
+	}{
+		{"Empty constraint", "1.0.0", "", true, false},
+		{"Within range", "1.5.0", ">=1.2.0 <2.0.0", true, false},
+		{"Below range", "1.1.0", ">=1.2.0 <2.0.0", false, false},
+		{"Above range", "2.0.0", ">=1.2.0 <2.0.0", false, false},
+		{"Caret range", "2.3.1", "^2.1", true, false},
+		{"Major wildcard", "1.9.9", "1.x", true, false},
+		{"With v prefix", "v1.5.0", ">=1.2", true, false},
+		{"Short version", "11", ">=10.15", true, false},
+		{"Invalid constraint", "1.0.0", "not a range", false, true},
+		{"Invalid version", "abc", ">=1.0.0", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := vc.SatisfiesConstraint(tt.version, tt.constraint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SatisfiesConstraint(%q, %q) error = %v, wantErr %v",
+					tt.version, tt.constraint, err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("SatisfiesConstraint(%q, %q) = %v, want %v",
+					tt.version, tt.constraint, result, tt.expected)
+			}
+		})
+	}
+}
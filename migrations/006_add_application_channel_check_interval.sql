@@ -0,0 +1,5 @@
+-- 006_add_application_channel_check_interval.sql
+-- Seconds between update checks hinted to the clients of an application channel,
+-- 0 uses the server default (CHECK_INTERVAL)
+
+ALTER TABLE application_channels ADD COLUMN check_interval integer DEFAULT 0;
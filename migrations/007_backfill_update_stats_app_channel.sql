@@ -0,0 +1,34 @@
+-- 007_backfill_update_stats_app_channel.sql
+-- Update stats are recorded with their application and channel. Older stats have neither,
+-- so assign them where the application can be told unambiguously from the versions and
+-- API keys; the rest stay unassigned and only show up in unfiltered statistics.
+-- The client inventory can't be used, it is only filled by checks made after the upgrade.
+
+-- The version number exists in a single application
+UPDATE update_stats
+SET app_id = (SELECT MIN(versions.app_id) FROM versions WHERE versions.version = update_stats.version)
+WHERE (app_id IS NULL OR app_id = '')
+  AND version IN (
+    SELECT version FROM versions GROUP BY version HAVING COUNT(DISTINCT app_id) = 1
+  );
+
+-- A single application has API keys that were used, so it reported all stats
+UPDATE update_stats
+SET app_id = (SELECT MIN(application_keys.app_id) FROM application_keys WHERE application_keys.last_used IS NOT NULL)
+WHERE (app_id IS NULL OR app_id = '')
+  AND (
+    SELECT COUNT(DISTINCT application_keys.app_id) FROM application_keys WHERE application_keys.last_used IS NOT NULL
+  ) = 1;
+
+-- Stats are in the channel of their version within the application
+UPDATE update_stats
+SET channel = (
+    SELECT MIN(versions.channel) FROM versions
+    WHERE versions.app_id = update_stats.app_id AND versions.version = update_stats.version
+  )
+WHERE (channel IS NULL OR channel = '')
+  AND app_id IS NOT NULL AND app_id != ''
+  AND EXISTS (
+    SELECT 1 FROM versions
+    WHERE versions.app_id = update_stats.app_id AND versions.version = update_stats.version
+  );
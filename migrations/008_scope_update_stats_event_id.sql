@@ -0,0 +1,6 @@
+-- 008_scope_update_stats_event_id.sql
+-- Telemetry event IDs are generated by clients and only unique per application and client.
+-- The composite index idx_update_stats_app_client_event replaces the table-wide one, which
+-- dropped the events of a client whose IDs another client had already used.
+
+DROP INDEX IF EXISTS idx_update_stats_event_id;
@@ -0,0 +1,1084 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: vertree/v1/update.proto
+
+package vertreev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CheckUpdateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to the authenticated application
+	AppId          string `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	CurrentVersion string `protobuf:"bytes,2,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+	Channel        string `protobuf:"bytes,3,opt,name=channel,proto3" json:"channel,omitempty"`
+	ClientId       string `protobuf:"bytes,4,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Region         string `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+	Arch           string `protobuf:"bytes,6,opt,name=arch,proto3" json:"arch,omitempty"`
+	Os             string `protobuf:"bytes,7,opt,name=os,proto3" json:"os,omitempty"`
+	// Preferred locale(s); falls back to the "accept-language" metadata
+	Locale           string            `protobuf:"bytes,8,opt,name=locale,proto3" json:"locale,omitempty"`
+	IncludeChangelog bool              `protobuf:"varint,9,opt,name=include_changelog,json=includeChangelog,proto3" json:"include_changelog,omitempty"`
+	ReleaseLine      string            `protobuf:"bytes,10,opt,name=release_line,json=releaseLine,proto3" json:"release_line,omitempty"`
+	OsVersion        string            `protobuf:"bytes,11,opt,name=os_version,json=osVersion,proto3" json:"os_version,omitempty"`
+	Dependencies     map[string]string `protobuf:"bytes,12,rep,name=dependencies,proto3" json:"dependencies,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Capabilities     []string          `protobuf:"bytes,13,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CheckUpdateRequest) Reset() {
+	*x = CheckUpdateRequest{}
+	mi := &file_vertree_v1_update_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckUpdateRequest) ProtoMessage() {}
+
+func (x *CheckUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vertree_v1_update_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckUpdateRequest.ProtoReflect.Descriptor instead.
+func (*CheckUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_vertree_v1_update_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CheckUpdateRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *CheckUpdateRequest) GetCurrentVersion() string {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return ""
+}
+
+func (x *CheckUpdateRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *CheckUpdateRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *CheckUpdateRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *CheckUpdateRequest) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *CheckUpdateRequest) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *CheckUpdateRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *CheckUpdateRequest) GetIncludeChangelog() bool {
+	if x != nil {
+		return x.IncludeChangelog
+	}
+	return false
+}
+
+func (x *CheckUpdateRequest) GetReleaseLine() string {
+	if x != nil {
+		return x.ReleaseLine
+	}
+	return ""
+}
+
+func (x *CheckUpdateRequest) GetOsVersion() string {
+	if x != nil {
+		return x.OsVersion
+	}
+	return ""
+}
+
+func (x *CheckUpdateRequest) GetDependencies() map[string]string {
+	if x != nil {
+		return x.Dependencies
+	}
+	return nil
+}
+
+func (x *CheckUpdateRequest) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+type CheckUpdateResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	HasUpdate         bool                   `protobuf:"varint,1,opt,name=has_update,json=hasUpdate,proto3" json:"has_update,omitempty"`
+	LatestVersion     string                 `protobuf:"bytes,2,opt,name=latest_version,json=latestVersion,proto3" json:"latest_version,omitempty"`
+	DownloadUrl       string                 `protobuf:"bytes,3,opt,name=download_url,json=downloadUrl,proto3" json:"download_url,omitempty"`
+	FileSize          int64                  `protobuf:"varint,4,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	FileChecksum      string                 `protobuf:"bytes,5,opt,name=file_checksum,json=fileChecksum,proto3" json:"file_checksum,omitempty"`
+	IsForced          bool                   `protobuf:"varint,6,opt,name=is_forced,json=isForced,proto3" json:"is_forced,omitempty"`
+	Title             string                 `protobuf:"bytes,7,opt,name=title,proto3" json:"title,omitempty"`
+	Description       string                 `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	ReleaseNotes      string                 `protobuf:"bytes,9,opt,name=release_notes,json=releaseNotes,proto3" json:"release_notes,omitempty"`
+	MinUpgradeVersion string                 `protobuf:"bytes,10,opt,name=min_upgrade_version,json=minUpgradeVersion,proto3" json:"min_upgrade_version,omitempty"`
+	Locale            string                 `protobuf:"bytes,11,opt,name=locale,proto3" json:"locale,omitempty"`
+	ReleaseLine       string                 `protobuf:"bytes,12,opt,name=release_line,json=releaseLine,proto3" json:"release_line,omitempty"`
+	Changelog         []*ChangelogEntry      `protobuf:"bytes,13,rep,name=changelog,proto3" json:"changelog,omitempty"`
+	SupportStatus     *SupportStatus         `protobuf:"bytes,14,opt,name=support_status,json=supportStatus,proto3" json:"support_status,omitempty"`
+	// Seconds the client should wait before checking for updates again
+	NextCheckAfter int32 `protobuf:"varint,15,opt,name=next_check_after,json=nextCheckAfter,proto3" json:"next_check_after,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CheckUpdateResponse) Reset() {
+	*x = CheckUpdateResponse{}
+	mi := &file_vertree_v1_update_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckUpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckUpdateResponse) ProtoMessage() {}
+
+func (x *CheckUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vertree_v1_update_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckUpdateResponse.ProtoReflect.Descriptor instead.
+func (*CheckUpdateResponse) Descriptor() ([]byte, []int) {
+	return file_vertree_v1_update_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CheckUpdateResponse) GetHasUpdate() bool {
+	if x != nil {
+		return x.HasUpdate
+	}
+	return false
+}
+
+func (x *CheckUpdateResponse) GetLatestVersion() string {
+	if x != nil {
+		return x.LatestVersion
+	}
+	return ""
+}
+
+func (x *CheckUpdateResponse) GetDownloadUrl() string {
+	if x != nil {
+		return x.DownloadUrl
+	}
+	return ""
+}
+
+func (x *CheckUpdateResponse) GetFileSize() int64 {
+	if x != nil {
+		return x.FileSize
+	}
+	return 0
+}
+
+func (x *CheckUpdateResponse) GetFileChecksum() string {
+	if x != nil {
+		return x.FileChecksum
+	}
+	return ""
+}
+
+func (x *CheckUpdateResponse) GetIsForced() bool {
+	if x != nil {
+		return x.IsForced
+	}
+	return false
+}
+
+func (x *CheckUpdateResponse) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CheckUpdateResponse) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CheckUpdateResponse) GetReleaseNotes() string {
+	if x != nil {
+		return x.ReleaseNotes
+	}
+	return ""
+}
+
+func (x *CheckUpdateResponse) GetMinUpgradeVersion() string {
+	if x != nil {
+		return x.MinUpgradeVersion
+	}
+	return ""
+}
+
+func (x *CheckUpdateResponse) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *CheckUpdateResponse) GetReleaseLine() string {
+	if x != nil {
+		return x.ReleaseLine
+	}
+	return ""
+}
+
+func (x *CheckUpdateResponse) GetChangelog() []*ChangelogEntry {
+	if x != nil {
+		return x.Changelog
+	}
+	return nil
+}
+
+func (x *CheckUpdateResponse) GetSupportStatus() *SupportStatus {
+	if x != nil {
+		return x.SupportStatus
+	}
+	return nil
+}
+
+func (x *CheckUpdateResponse) GetNextCheckAfter() int32 {
+	if x != nil {
+		return x.NextCheckAfter
+	}
+	return 0
+}
+
+type ChangelogEntry struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Version         string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Title           string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	ReleaseNotes    string                 `protobuf:"bytes,3,opt,name=release_notes,json=releaseNotes,proto3" json:"release_notes,omitempty"`
+	BreakingChanges string                 `protobuf:"bytes,4,opt,name=breaking_changes,json=breakingChanges,proto3" json:"breaking_changes,omitempty"`
+	IsForced        bool                   `protobuf:"varint,5,opt,name=is_forced,json=isForced,proto3" json:"is_forced,omitempty"`
+	PublishTime     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=publish_time,json=publishTime,proto3" json:"publish_time,omitempty"`
+	Locale          string                 `protobuf:"bytes,7,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangelogEntry) Reset() {
+	*x = ChangelogEntry{}
+	mi := &file_vertree_v1_update_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangelogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangelogEntry) ProtoMessage() {}
+
+func (x *ChangelogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_vertree_v1_update_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangelogEntry.ProtoReflect.Descriptor instead.
+func (*ChangelogEntry) Descriptor() ([]byte, []int) {
+	return file_vertree_v1_update_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChangelogEntry) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ChangelogEntry) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ChangelogEntry) GetReleaseNotes() string {
+	if x != nil {
+		return x.ReleaseNotes
+	}
+	return ""
+}
+
+func (x *ChangelogEntry) GetBreakingChanges() string {
+	if x != nil {
+		return x.BreakingChanges
+	}
+	return ""
+}
+
+func (x *ChangelogEntry) GetIsForced() bool {
+	if x != nil {
+		return x.IsForced
+	}
+	return false
+}
+
+func (x *ChangelogEntry) GetPublishTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishTime
+	}
+	return nil
+}
+
+func (x *ChangelogEntry) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type SupportStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// supported, deprecated, eol or unknown
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	DeprecatedAt  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=deprecated_at,json=deprecatedAt,proto3" json:"deprecated_at,omitempty"`
+	EolAt         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=eol_at,json=eolAt,proto3" json:"eol_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SupportStatus) Reset() {
+	*x = SupportStatus{}
+	mi := &file_vertree_v1_update_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SupportStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SupportStatus) ProtoMessage() {}
+
+func (x *SupportStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_vertree_v1_update_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SupportStatus.ProtoReflect.Descriptor instead.
+func (*SupportStatus) Descriptor() ([]byte, []int) {
+	return file_vertree_v1_update_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SupportStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SupportStatus) GetDeprecatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeprecatedAt
+	}
+	return nil
+}
+
+func (x *SupportStatus) GetEolAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EolAt
+	}
+	return nil
+}
+
+type DownloadStartedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	ClientId      string                 `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadStartedRequest) Reset() {
+	*x = DownloadStartedRequest{}
+	mi := &file_vertree_v1_update_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadStartedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadStartedRequest) ProtoMessage() {}
+
+func (x *DownloadStartedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vertree_v1_update_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadStartedRequest.ProtoReflect.Descriptor instead.
+func (*DownloadStartedRequest) Descriptor() ([]byte, []int) {
+	return file_vertree_v1_update_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DownloadStartedRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *DownloadStartedRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type DownloadStartedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadStartedResponse) Reset() {
+	*x = DownloadStartedResponse{}
+	mi := &file_vertree_v1_update_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadStartedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadStartedResponse) ProtoMessage() {}
+
+func (x *DownloadStartedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vertree_v1_update_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadStartedResponse.ProtoReflect.Descriptor instead.
+func (*DownloadStartedResponse) Descriptor() ([]byte, []int) {
+	return file_vertree_v1_update_proto_rawDescGZIP(), []int{5}
+}
+
+type InstallResultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	ClientId      string                 `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Success       bool                   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstallResultRequest) Reset() {
+	*x = InstallResultRequest{}
+	mi := &file_vertree_v1_update_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstallResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstallResultRequest) ProtoMessage() {}
+
+func (x *InstallResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vertree_v1_update_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstallResultRequest.ProtoReflect.Descriptor instead.
+func (*InstallResultRequest) Descriptor() ([]byte, []int) {
+	return file_vertree_v1_update_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *InstallResultRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *InstallResultRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *InstallResultRequest) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *InstallResultRequest) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type InstallResultResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstallResultResponse) Reset() {
+	*x = InstallResultResponse{}
+	mi := &file_vertree_v1_update_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstallResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstallResultResponse) ProtoMessage() {}
+
+func (x *InstallResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vertree_v1_update_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstallResultResponse.ProtoReflect.Descriptor instead.
+func (*InstallResultResponse) Descriptor() ([]byte, []int) {
+	return file_vertree_v1_update_proto_rawDescGZIP(), []int{7}
+}
+
+type GetVersionsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Channel string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	// Defaults to 10
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Defaults to true
+	PublishedOnly *bool `protobuf:"varint,3,opt,name=published_only,json=publishedOnly,proto3,oneof" json:"published_only,omitempty"`
+	// Preferred locale(s); falls back to the "accept-language" metadata
+	Locale        string `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVersionsRequest) Reset() {
+	*x = GetVersionsRequest{}
+	mi := &file_vertree_v1_update_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersionsRequest) ProtoMessage() {}
+
+func (x *GetVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vertree_v1_update_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersionsRequest.ProtoReflect.Descriptor instead.
+func (*GetVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_vertree_v1_update_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetVersionsRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *GetVersionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetVersionsRequest) GetPublishedOnly() bool {
+	if x != nil && x.PublishedOnly != nil {
+		return *x.PublishedOnly
+	}
+	return false
+}
+
+func (x *GetVersionsRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type GetVersionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []*VersionInfo         `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVersionsResponse) Reset() {
+	*x = GetVersionsResponse{}
+	mi := &file_vertree_v1_update_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersionsResponse) ProtoMessage() {}
+
+func (x *GetVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vertree_v1_update_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersionsResponse.ProtoReflect.Descriptor instead.
+func (*GetVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_vertree_v1_update_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetVersionsResponse) GetVersions() []*VersionInfo {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type VersionInfo struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Version           string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Channel           string                 `protobuf:"bytes,2,opt,name=channel,proto3" json:"channel,omitempty"`
+	Title             string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description       string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	ReleaseNotes      string                 `protobuf:"bytes,5,opt,name=release_notes,json=releaseNotes,proto3" json:"release_notes,omitempty"`
+	DownloadUrl       string                 `protobuf:"bytes,6,opt,name=download_url,json=downloadUrl,proto3" json:"download_url,omitempty"`
+	FileSize          int64                  `protobuf:"varint,7,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	FileChecksum      string                 `protobuf:"bytes,8,opt,name=file_checksum,json=fileChecksum,proto3" json:"file_checksum,omitempty"`
+	IsForced          bool                   `protobuf:"varint,9,opt,name=is_forced,json=isForced,proto3" json:"is_forced,omitempty"`
+	MinUpgradeVersion string                 `protobuf:"bytes,10,opt,name=min_upgrade_version,json=minUpgradeVersion,proto3" json:"min_upgrade_version,omitempty"`
+	Locale            string                 `protobuf:"bytes,11,opt,name=locale,proto3" json:"locale,omitempty"`
+	PublishedAt       *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *VersionInfo) Reset() {
+	*x = VersionInfo{}
+	mi := &file_vertree_v1_update_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionInfo) ProtoMessage() {}
+
+func (x *VersionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_vertree_v1_update_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionInfo.ProtoReflect.Descriptor instead.
+func (*VersionInfo) Descriptor() ([]byte, []int) {
+	return file_vertree_v1_update_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *VersionInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetReleaseNotes() string {
+	if x != nil {
+		return x.ReleaseNotes
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetDownloadUrl() string {
+	if x != nil {
+		return x.DownloadUrl
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetFileSize() int64 {
+	if x != nil {
+		return x.FileSize
+	}
+	return 0
+}
+
+func (x *VersionInfo) GetFileChecksum() string {
+	if x != nil {
+		return x.FileChecksum
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetIsForced() bool {
+	if x != nil {
+		return x.IsForced
+	}
+	return false
+}
+
+func (x *VersionInfo) GetMinUpgradeVersion() string {
+	if x != nil {
+		return x.MinUpgradeVersion
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetPublishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return nil
+}
+
+var File_vertree_v1_update_proto protoreflect.FileDescriptor
+
+const file_vertree_v1_update_proto_rawDesc = "" +
+	"\n" +
+	"\x17vertree/v1/update.proto\x12\n" +
+	"vertree.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x89\x04\n" +
+	"\x12CheckUpdateRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12'\n" +
+	"\x0fcurrent_version\x18\x02 \x01(\tR\x0ecurrentVersion\x12\x18\n" +
+	"\achannel\x18\x03 \x01(\tR\achannel\x12\x1b\n" +
+	"\tclient_id\x18\x04 \x01(\tR\bclientId\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\x12\x12\n" +
+	"\x04arch\x18\x06 \x01(\tR\x04arch\x12\x0e\n" +
+	"\x02os\x18\a \x01(\tR\x02os\x12\x16\n" +
+	"\x06locale\x18\b \x01(\tR\x06locale\x12+\n" +
+	"\x11include_changelog\x18\t \x01(\bR\x10includeChangelog\x12!\n" +
+	"\frelease_line\x18\n" +
+	" \x01(\tR\vreleaseLine\x12\x1d\n" +
+	"\n" +
+	"os_version\x18\v \x01(\tR\tosVersion\x12T\n" +
+	"\fdependencies\x18\f \x03(\v20.vertree.v1.CheckUpdateRequest.DependenciesEntryR\fdependencies\x12\"\n" +
+	"\fcapabilities\x18\r \x03(\tR\fcapabilities\x1a?\n" +
+	"\x11DependenciesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcb\x04\n" +
+	"\x13CheckUpdateResponse\x12\x1d\n" +
+	"\n" +
+	"has_update\x18\x01 \x01(\bR\thasUpdate\x12%\n" +
+	"\x0elatest_version\x18\x02 \x01(\tR\rlatestVersion\x12!\n" +
+	"\fdownload_url\x18\x03 \x01(\tR\vdownloadUrl\x12\x1b\n" +
+	"\tfile_size\x18\x04 \x01(\x03R\bfileSize\x12#\n" +
+	"\rfile_checksum\x18\x05 \x01(\tR\ffileChecksum\x12\x1b\n" +
+	"\tis_forced\x18\x06 \x01(\bR\bisForced\x12\x14\n" +
+	"\x05title\x18\a \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\b \x01(\tR\vdescription\x12#\n" +
+	"\rrelease_notes\x18\t \x01(\tR\freleaseNotes\x12.\n" +
+	"\x13min_upgrade_version\x18\n" +
+	" \x01(\tR\x11minUpgradeVersion\x12\x16\n" +
+	"\x06locale\x18\v \x01(\tR\x06locale\x12!\n" +
+	"\frelease_line\x18\f \x01(\tR\vreleaseLine\x128\n" +
+	"\tchangelog\x18\r \x03(\v2\x1a.vertree.v1.ChangelogEntryR\tchangelog\x12@\n" +
+	"\x0esupport_status\x18\x0e \x01(\v2\x19.vertree.v1.SupportStatusR\rsupportStatus\x12(\n" +
+	"\x10next_check_after\x18\x0f \x01(\x05R\x0enextCheckAfter\"\x84\x02\n" +
+	"\x0eChangelogEntry\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12#\n" +
+	"\rrelease_notes\x18\x03 \x01(\tR\freleaseNotes\x12)\n" +
+	"\x10breaking_changes\x18\x04 \x01(\tR\x0fbreakingChanges\x12\x1b\n" +
+	"\tis_forced\x18\x05 \x01(\bR\bisForced\x12=\n" +
+	"\fpublish_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vpublishTime\x12\x16\n" +
+	"\x06locale\x18\a \x01(\tR\x06locale\"\x9b\x01\n" +
+	"\rSupportStatus\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12?\n" +
+	"\rdeprecated_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\fdeprecatedAt\x121\n" +
+	"\x06eol_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05eolAt\"O\n" +
+	"\x16DownloadStartedRequest\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\"\x19\n" +
+	"\x17DownloadStartedResponse\"\x8c\x01\n" +
+	"\x14InstallResultRequest\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12\x18\n" +
+	"\asuccess\x18\x03 \x01(\bR\asuccess\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\"\x17\n" +
+	"\x15InstallResultResponse\"\x9b\x01\n" +
+	"\x12GetVersionsRequest\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12*\n" +
+	"\x0epublished_only\x18\x03 \x01(\bH\x00R\rpublishedOnly\x88\x01\x01\x12\x16\n" +
+	"\x06locale\x18\x04 \x01(\tR\x06localeB\x11\n" +
+	"\x0f_published_only\"J\n" +
+	"\x13GetVersionsResponse\x123\n" +
+	"\bversions\x18\x01 \x03(\v2\x17.vertree.v1.VersionInfoR\bversions\"\xa7\x03\n" +
+	"\vVersionInfo\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x18\n" +
+	"\achannel\x18\x02 \x01(\tR\achannel\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12#\n" +
+	"\rrelease_notes\x18\x05 \x01(\tR\freleaseNotes\x12!\n" +
+	"\fdownload_url\x18\x06 \x01(\tR\vdownloadUrl\x12\x1b\n" +
+	"\tfile_size\x18\a \x01(\x03R\bfileSize\x12#\n" +
+	"\rfile_checksum\x18\b \x01(\tR\ffileChecksum\x12\x1b\n" +
+	"\tis_forced\x18\t \x01(\bR\bisForced\x12.\n" +
+	"\x13min_upgrade_version\x18\n" +
+	" \x01(\tR\x11minUpgradeVersion\x12\x16\n" +
+	"\x06locale\x18\v \x01(\tR\x06locale\x12=\n" +
+	"\fpublished_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vpublishedAt2\xe1\x02\n" +
+	"\rUpdateService\x12N\n" +
+	"\vCheckUpdate\x12\x1e.vertree.v1.CheckUpdateRequest\x1a\x1f.vertree.v1.CheckUpdateResponse\x12Z\n" +
+	"\x0fDownloadStarted\x12\".vertree.v1.DownloadStartedRequest\x1a#.vertree.v1.DownloadStartedResponse\x12T\n" +
+	"\rInstallResult\x12 .vertree.v1.InstallResultRequest\x1a!.vertree.v1.InstallResultResponse\x12N\n" +
+	"\vGetVersions\x12\x1e.vertree.v1.GetVersionsRequest\x1a\x1f.vertree.v1.GetVersionsResponseB;Z9github.com/Run-Panel/VerTree/pkg/api/vertree/v1;vertreev1b\x06proto3"
+
+var (
+	file_vertree_v1_update_proto_rawDescOnce sync.Once
+	file_vertree_v1_update_proto_rawDescData []byte
+)
+
+func file_vertree_v1_update_proto_rawDescGZIP() []byte {
+	file_vertree_v1_update_proto_rawDescOnce.Do(func() {
+		file_vertree_v1_update_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vertree_v1_update_proto_rawDesc), len(file_vertree_v1_update_proto_rawDesc)))
+	})
+	return file_vertree_v1_update_proto_rawDescData
+}
+
+var file_vertree_v1_update_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_vertree_v1_update_proto_goTypes = []any{
+	(*CheckUpdateRequest)(nil),      // 0: vertree.v1.CheckUpdateRequest
+	(*CheckUpdateResponse)(nil),     // 1: vertree.v1.CheckUpdateResponse
+	(*ChangelogEntry)(nil),          // 2: vertree.v1.ChangelogEntry
+	(*SupportStatus)(nil),           // 3: vertree.v1.SupportStatus
+	(*DownloadStartedRequest)(nil),  // 4: vertree.v1.DownloadStartedRequest
+	(*DownloadStartedResponse)(nil), // 5: vertree.v1.DownloadStartedResponse
+	(*InstallResultRequest)(nil),    // 6: vertree.v1.InstallResultRequest
+	(*InstallResultResponse)(nil),   // 7: vertree.v1.InstallResultResponse
+	(*GetVersionsRequest)(nil),      // 8: vertree.v1.GetVersionsRequest
+	(*GetVersionsResponse)(nil),     // 9: vertree.v1.GetVersionsResponse
+	(*VersionInfo)(nil),             // 10: vertree.v1.VersionInfo
+	nil,                             // 11: vertree.v1.CheckUpdateRequest.DependenciesEntry
+	(*timestamppb.Timestamp)(nil),   // 12: google.protobuf.Timestamp
+}
+var file_vertree_v1_update_proto_depIdxs = []int32{
+	11, // 0: vertree.v1.CheckUpdateRequest.dependencies:type_name -> vertree.v1.CheckUpdateRequest.DependenciesEntry
+	2,  // 1: vertree.v1.CheckUpdateResponse.changelog:type_name -> vertree.v1.ChangelogEntry
+	3,  // 2: vertree.v1.CheckUpdateResponse.support_status:type_name -> vertree.v1.SupportStatus
+	12, // 3: vertree.v1.ChangelogEntry.publish_time:type_name -> google.protobuf.Timestamp
+	12, // 4: vertree.v1.SupportStatus.deprecated_at:type_name -> google.protobuf.Timestamp
+	12, // 5: vertree.v1.SupportStatus.eol_at:type_name -> google.protobuf.Timestamp
+	10, // 6: vertree.v1.GetVersionsResponse.versions:type_name -> vertree.v1.VersionInfo
+	12, // 7: vertree.v1.VersionInfo.published_at:type_name -> google.protobuf.Timestamp
+	0,  // 8: vertree.v1.UpdateService.CheckUpdate:input_type -> vertree.v1.CheckUpdateRequest
+	4,  // 9: vertree.v1.UpdateService.DownloadStarted:input_type -> vertree.v1.DownloadStartedRequest
+	6,  // 10: vertree.v1.UpdateService.InstallResult:input_type -> vertree.v1.InstallResultRequest
+	8,  // 11: vertree.v1.UpdateService.GetVersions:input_type -> vertree.v1.GetVersionsRequest
+	1,  // 12: vertree.v1.UpdateService.CheckUpdate:output_type -> vertree.v1.CheckUpdateResponse
+	5,  // 13: vertree.v1.UpdateService.DownloadStarted:output_type -> vertree.v1.DownloadStartedResponse
+	7,  // 14: vertree.v1.UpdateService.InstallResult:output_type -> vertree.v1.InstallResultResponse
+	9,  // 15: vertree.v1.UpdateService.GetVersions:output_type -> vertree.v1.GetVersionsResponse
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_vertree_v1_update_proto_init() }
+func file_vertree_v1_update_proto_init() {
+	if File_vertree_v1_update_proto != nil {
+		return
+	}
+	file_vertree_v1_update_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vertree_v1_update_proto_rawDesc), len(file_vertree_v1_update_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vertree_v1_update_proto_goTypes,
+		DependencyIndexes: file_vertree_v1_update_proto_depIdxs,
+		MessageInfos:      file_vertree_v1_update_proto_msgTypes,
+	}.Build()
+	File_vertree_v1_update_proto = out.File
+	file_vertree_v1_update_proto_goTypes = nil
+	file_vertree_v1_update_proto_depIdxs = nil
+}
@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: vertree/v1/update.proto
+
+package vertreev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UpdateService_CheckUpdate_FullMethodName     = "/vertree.v1.UpdateService/CheckUpdate"
+	UpdateService_DownloadStarted_FullMethodName = "/vertree.v1.UpdateService/DownloadStarted"
+	UpdateService_InstallResult_FullMethodName   = "/vertree.v1.UpdateService/InstallResult"
+	UpdateService_GetVersions_FullMethodName     = "/vertree.v1.UpdateService/GetVersions"
+)
+
+// UpdateServiceClient is the client API for UpdateService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UpdateService mirrors the client REST API (/api/v1). Every call must carry the
+// "authorization" metadata "Bearer <app_id>:<api_key>"; the API key needs the same
+// permissions as for the REST endpoints (check_update, download, install).
+type UpdateServiceClient interface {
+	// CheckUpdate checks whether a newer version is available (POST /api/v1/check-update)
+	CheckUpdate(ctx context.Context, in *CheckUpdateRequest, opts ...grpc.CallOption) (*CheckUpdateResponse, error)
+	// DownloadStarted records that a client started downloading a version (POST /api/v1/download-started)
+	DownloadStarted(ctx context.Context, in *DownloadStartedRequest, opts ...grpc.CallOption) (*DownloadStartedResponse, error)
+	// InstallResult records the result of installing a version (POST /api/v1/install-result)
+	InstallResult(ctx context.Context, in *InstallResultRequest, opts ...grpc.CallOption) (*InstallResultResponse, error)
+	// GetVersions lists the versions of the application (GET /api/v1/versions)
+	GetVersions(ctx context.Context, in *GetVersionsRequest, opts ...grpc.CallOption) (*GetVersionsResponse, error)
+}
+
+type updateServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUpdateServiceClient(cc grpc.ClientConnInterface) UpdateServiceClient {
+	return &updateServiceClient{cc}
+}
+
+func (c *updateServiceClient) CheckUpdate(ctx context.Context, in *CheckUpdateRequest, opts ...grpc.CallOption) (*CheckUpdateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckUpdateResponse)
+	err := c.cc.Invoke(ctx, UpdateService_CheckUpdate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateServiceClient) DownloadStarted(ctx context.Context, in *DownloadStartedRequest, opts ...grpc.CallOption) (*DownloadStartedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DownloadStartedResponse)
+	err := c.cc.Invoke(ctx, UpdateService_DownloadStarted_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateServiceClient) InstallResult(ctx context.Context, in *InstallResultRequest, opts ...grpc.CallOption) (*InstallResultResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InstallResultResponse)
+	err := c.cc.Invoke(ctx, UpdateService_InstallResult_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateServiceClient) GetVersions(ctx context.Context, in *GetVersionsRequest, opts ...grpc.CallOption) (*GetVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVersionsResponse)
+	err := c.cc.Invoke(ctx, UpdateService_GetVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateServiceServer is the server API for UpdateService service.
+// All implementations must embed UnimplementedUpdateServiceServer
+// for forward compatibility.
+//
+// UpdateService mirrors the client REST API (/api/v1). Every call must carry the
+// "authorization" metadata "Bearer <app_id>:<api_key>"; the API key needs the same
+// permissions as for the REST endpoints (check_update, download, install).
+type UpdateServiceServer interface {
+	// CheckUpdate checks whether a newer version is available (POST /api/v1/check-update)
+	CheckUpdate(context.Context, *CheckUpdateRequest) (*CheckUpdateResponse, error)
+	// DownloadStarted records that a client started downloading a version (POST /api/v1/download-started)
+	DownloadStarted(context.Context, *DownloadStartedRequest) (*DownloadStartedResponse, error)
+	// InstallResult records the result of installing a version (POST /api/v1/install-result)
+	InstallResult(context.Context, *InstallResultRequest) (*InstallResultResponse, error)
+	// GetVersions lists the versions of the application (GET /api/v1/versions)
+	GetVersions(context.Context, *GetVersionsRequest) (*GetVersionsResponse, error)
+	mustEmbedUnimplementedUpdateServiceServer()
+}
+
+// UnimplementedUpdateServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUpdateServiceServer struct{}
+
+func (UnimplementedUpdateServiceServer) CheckUpdate(context.Context, *CheckUpdateRequest) (*CheckUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckUpdate not implemented")
+}
+func (UnimplementedUpdateServiceServer) DownloadStarted(context.Context, *DownloadStartedRequest) (*DownloadStartedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DownloadStarted not implemented")
+}
+func (UnimplementedUpdateServiceServer) InstallResult(context.Context, *InstallResultRequest) (*InstallResultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InstallResult not implemented")
+}
+func (UnimplementedUpdateServiceServer) GetVersions(context.Context, *GetVersionsRequest) (*GetVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersions not implemented")
+}
+func (UnimplementedUpdateServiceServer) mustEmbedUnimplementedUpdateServiceServer() {}
+func (UnimplementedUpdateServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeUpdateServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UpdateServiceServer will
+// result in compilation errors.
+type UnsafeUpdateServiceServer interface {
+	mustEmbedUnimplementedUpdateServiceServer()
+}
+
+func RegisterUpdateServiceServer(s grpc.ServiceRegistrar, srv UpdateServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUpdateServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UpdateService_ServiceDesc, srv)
+}
+
+func _UpdateService_CheckUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServiceServer).CheckUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpdateService_CheckUpdate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServiceServer).CheckUpdate(ctx, req.(*CheckUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpdateService_DownloadStarted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DownloadStartedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServiceServer).DownloadStarted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpdateService_DownloadStarted_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServiceServer).DownloadStarted(ctx, req.(*DownloadStartedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpdateService_InstallResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstallResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServiceServer).InstallResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpdateService_InstallResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServiceServer).InstallResult(ctx, req.(*InstallResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpdateService_GetVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServiceServer).GetVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpdateService_GetVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServiceServer).GetVersions(ctx, req.(*GetVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UpdateService_ServiceDesc is the grpc.ServiceDesc for UpdateService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UpdateService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vertree.v1.UpdateService",
+	HandlerType: (*UpdateServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CheckUpdate",
+			Handler:    _UpdateService_CheckUpdate_Handler,
+		},
+		{
+			MethodName: "DownloadStarted",
+			Handler:    _UpdateService_DownloadStarted_Handler,
+		},
+		{
+			MethodName: "InstallResult",
+			Handler:    _UpdateService_InstallResult_Handler,
+		},
+		{
+			MethodName: "GetVersions",
+			Handler:    _UpdateService_GetVersions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vertree/v1/update.proto",
+}
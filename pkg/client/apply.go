@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Apply atomically replaces target with the file at src, keeping the file mode of target.
+// The new file is staged next to target and renamed over it, so target is never left
+// partially written. On Windows, where a running executable can't be overwritten, target
+// is first renamed to target + ".old", which can be removed once the new version runs.
+func Apply(src, target string) error {
+	stat, err := os.Stat(target)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", target, err)
+	}
+
+	staged, err := stage(src, target, stat.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		old := target + ".old"
+		os.Remove(old)
+		if err := os.Rename(target, old); err != nil {
+			os.Remove(staged)
+			return fmt.Errorf("failed to move %s aside: %w", target, err)
+		}
+		if err := os.Rename(staged, target); err != nil {
+			os.Rename(old, target)
+			os.Remove(staged)
+			return fmt.Errorf("failed to replace %s: %w", target, err)
+		}
+		return nil
+	}
+
+	if err := os.Rename(staged, target); err != nil {
+		os.Remove(staged)
+		return fmt.Errorf("failed to replace %s: %w", target, err)
+	}
+	return nil
+}
+
+// stage copies src to a temporary file in the directory of target, so that it can be renamed
+// over target even when src is on another file system
+func stage(src, target string, mode os.FileMode) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".new-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging file: %w", err)
+	}
+
+	if _, err := io.Copy(out, in); err == nil {
+		err = out.Chmod(mode)
+	}
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(out.Name())
+		return "", fmt.Errorf("failed to stage %s: %w", src, err)
+	}
+
+	return out.Name(), nil
+}
+
+// SelfUpdate downloads the update, verifies it and replaces the running executable with it,
+// reporting the download and the install result to the server. Reporting errors are ignored
+// so that they don't prevent the update. An interrupted download is not reported as a failed
+// install and resumes on the next call. The new version is used after a restart.
+func (c *Client) SelfUpdate(ctx context.Context, info *UpdateInfo, clientID string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	c.DownloadStarted(ctx, info.LatestVersion, clientID)
+
+	// Download next to the executable so that an interrupted download resumes on the next run
+	download := executable + ".download"
+	err = c.Download(ctx, info, download)
+	if err != nil && !errors.Is(err, ErrChecksumMismatch) {
+		return err
+	}
+	if err == nil {
+		err = Apply(download, executable)
+		os.Remove(download)
+	}
+
+	c.InstallResult(ctx, info.LatestVersion, clientID, err)
+	return err
+}
@@ -0,0 +1,265 @@
+// Package client is the Go SDK of the VerTree client API. It checks for updates, reports
+// download and install progress, downloads updates with resume and checksum verification
+// and atomically replaces the running binary.
+//
+//	c := client.New("https://updates.example.com", "app_abc123", "sk_def456789")
+//	info, err := c.CheckUpdate(ctx, &client.CheckUpdateRequest{
+//		CurrentVersion: "1.2.2",
+//		Channel:        "stable",
+//		ClientID:       clientID,
+//	})
+//	if err == nil && info.HasUpdate {
+//		err = c.SelfUpdate(ctx, info, clientID)
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls the VerTree client API of an application
+type Client struct {
+	BaseURL    string       // Server URL, e.g. "https://updates.example.com"
+	AppID      string       // Application ID, e.g. "app_abc123"
+	APIKey     string       // API key secret of the application
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+	UserAgent  string
+}
+
+// New creates a client for the application appID authenticated with apiKey
+func New(baseURL, appID, apiKey string) *Client {
+	return &Client{
+		BaseURL:   strings.TrimRight(baseURL, "/"),
+		AppID:     appID,
+		APIKey:    apiKey,
+		UserAgent: "vertree-go-client",
+	}
+}
+
+// CheckUpdateRequest represents an update check. AppID defaults to the client's
+// application, OS and Arch to the platform the program runs on.
+type CheckUpdateRequest struct {
+	AppID            string            `json:"app_id"`
+	CurrentVersion   string            `json:"current_version"`
+	Channel          string            `json:"channel"`
+	ClientID         string            `json:"client_id"`
+	Region           string            `json:"region,omitempty"`
+	Arch             string            `json:"arch,omitempty"`
+	OS               string            `json:"os,omitempty"`
+	Locale           string            `json:"locale,omitempty"`
+	IncludeChangelog bool              `json:"include_changelog,omitempty"`
+	ReleaseLine      string            `json:"release_line,omitempty"`
+	OSVersion        string            `json:"os_version,omitempty"`
+	Dependencies     map[string]string `json:"dependencies,omitempty"`
+	Capabilities     []string          `json:"capabilities,omitempty"`
+}
+
+// UpdateInfo represents the result of an update check
+type UpdateInfo struct {
+	HasUpdate         bool             `json:"has_update"`
+	LatestVersion     string           `json:"latest_version,omitempty"`
+	DownloadURL       string           `json:"download_url,omitempty"`
+	FileSize          int64            `json:"file_size,omitempty"`
+	FileChecksum      string           `json:"file_checksum,omitempty"`
+	IsForced          bool             `json:"is_forced,omitempty"`
+	Title             string           `json:"title,omitempty"`
+	Description       string           `json:"description,omitempty"`
+	ReleaseNotes      string           `json:"release_notes,omitempty"`
+	MinUpgradeVersion string           `json:"min_upgrade_version,omitempty"`
+	Locale            string           `json:"locale,omitempty"`
+	ReleaseLine       string           `json:"release_line,omitempty"`
+	Changelog         []ChangelogEntry `json:"changelog,omitempty"`
+	SupportStatus     *SupportStatus   `json:"support_status,omitempty"`
+	NextCheckAfter    int              `json:"next_check_after,omitempty"` // Seconds to wait before checking again
+}
+
+// NextCheck returns how long to wait before checking for updates again, or fallback when the
+// server didn't say
+func (i *UpdateInfo) NextCheck(fallback time.Duration) time.Duration {
+	if i.NextCheckAfter <= 0 {
+		return fallback
+	}
+	return time.Duration(i.NextCheckAfter) * time.Second
+}
+
+// ChangelogEntry represents the release information of one version in a changelog
+type ChangelogEntry struct {
+	Version         string     `json:"version"`
+	Title           string     `json:"title"`
+	ReleaseNotes    string     `json:"release_notes,omitempty"`
+	BreakingChanges string     `json:"breaking_changes,omitempty"`
+	IsForced        bool       `json:"is_forced,omitempty"`
+	PublishTime     *time.Time `json:"publish_time,omitempty"`
+	Locale          string     `json:"locale,omitempty"`
+}
+
+// SupportStatus represents the support status of the client's current version
+type SupportStatus struct {
+	Status       string     `json:"status"` // supported, deprecated, eol or unknown
+	DeprecatedAt *time.Time `json:"deprecated_at,omitempty"`
+	EOLAt        *time.Time `json:"eol_at,omitempty"`
+}
+
+// APIError is returned when the server answers with an error response
+type APIError struct {
+	StatusCode int
+	Message    string
+	Detail     string
+	RetryAfter time.Duration // How long to wait before retrying a rate limited request
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("vertree: %s (HTTP %d): %s", e.Message, e.StatusCode, e.Detail)
+	}
+	return fmt.Sprintf("vertree: %s (HTTP %d)", e.Message, e.StatusCode)
+}
+
+// apiResponse is the envelope of all client API responses
+type apiResponse struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+}
+
+// CheckUpdate checks whether a newer version is available
+func (c *Client) CheckUpdate(ctx context.Context, req *CheckUpdateRequest) (*UpdateInfo, error) {
+	body := *req
+	if body.AppID == "" {
+		body.AppID = c.AppID
+	}
+	if body.OS == "" {
+		body.OS = runtime.GOOS
+	}
+	if body.Arch == "" {
+		body.Arch = runtime.GOARCH
+	}
+
+	var info UpdateInfo
+	if err := c.post(ctx, "/api/v1/check-update", &body, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// DownloadStarted reports that the client started downloading version
+func (c *Client) DownloadStarted(ctx context.Context, version, clientID string) error {
+	return c.post(ctx, "/api/v1/download-started", map[string]string{
+		"version":   version,
+		"client_id": clientID,
+	}, nil)
+}
+
+// InstallResult reports the result of installing version. installErr is nil when the
+// install succeeded.
+func (c *Client) InstallResult(ctx context.Context, version, clientID string, installErr error) error {
+	body := map[string]interface{}{
+		"version":   version,
+		"client_id": clientID,
+		"success":   installErr == nil,
+	}
+	if installErr != nil {
+		body["error_message"] = installErr.Error()
+	}
+	return c.post(ctx, "/api/v1/install-result", body, nil)
+}
+
+// post sends a JSON request to the client API and decodes the data of the response into out
+func (c *Client) post(ctx context.Context, path string, in, out interface{}) error {
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.authorize(req)
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	var envelope apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		}
+		return fmt.Errorf("failed to decode response of %s: %w", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    envelope.Message,
+			Detail:     envelope.Error,
+			RetryAfter: retryAfter(resp),
+		}
+	}
+
+	if out != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return fmt.Errorf("failed to decode response of %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// authorize sets the "Bearer <app_id>:<api_key>" authorization header
+func (c *Client) authorize(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+c.AppID+":"+c.APIKey)
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+}
+
+// resolveURL resolves download URLs relative to the server, e.g. "/uploads/versions/..."
+func (c *Client) resolveURL(ref string) (string, error) {
+	base, err := url.Parse(c.BaseURL + "/")
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
+	target, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid download URL: %w", err)
+	}
+	return base.ResolveReference(target).String(), nil
+}
+
+// httpClient returns the HTTP client used for requests
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// retryAfter parses the Retry-After header of a response given in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// drain discards the rest of a response body so that the connection can be reused
+func drain(body io.ReadCloser) {
+	io.Copy(io.Discard, body)
+	body.Close()
+}
@@ -0,0 +1,264 @@
+package client_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Run-Panel/VerTree/internal/config"
+	"github.com/Run-Panel/VerTree/internal/database"
+	"github.com/Run-Panel/VerTree/internal/httpserver"
+	"github.com/Run-Panel/VerTree/internal/middleware"
+	"github.com/Run-Panel/VerTree/internal/models"
+	"github.com/Run-Panel/VerTree/internal/services"
+	"github.com/Run-Panel/VerTree/pkg/client"
+	"github.com/gin-gonic/gin"
+)
+
+// payload is the file of the published test version
+var payload = []byte("#!/bin/sh\necho 'vertree test binary 1.1.0'\n")
+
+var (
+	serverURL string
+	appID     string
+	apiKey    string
+)
+
+// TestMain runs the tests against the real router, backed by a SQLite database in a
+// temporary directory
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	migrations, err := filepath.Abs("../../migrations")
+	if err != nil {
+		log.Fatal(err)
+	}
+	dir, err := os.MkdirTemp("", "vertree-client-test-")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The database, migrations and uploads are all relative to the working directory
+	if err := os.Symlink(migrations, filepath.Join(dir, "migrations")); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		log.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{Driver: "sqlite", Name: "vertree"},
+		App:      config.AppConfig{Environment: "production", JWTSecret: "test-secret", TUFKeySecret: "test-secret"},
+	}
+	if err := database.Initialize(cfg); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer database.Close()
+	services.SetTUFKeySecret(cfg.App.TUFKeySecret)
+	if err := database.SeedDefaultData(); err != nil {
+		log.Fatalf("Failed to seed default data: %v", err)
+	}
+
+	if err := seed(); err != nil {
+		log.Fatalf("Failed to seed test data: %v", err)
+	}
+
+	server := httptest.NewServer(httpserver.NewRouter(cfg, middleware.CreateRateLimiters()))
+	defer server.Close()
+	serverURL = server.URL
+
+	return m.Run()
+}
+
+// seed creates an application with an API key and publishes version 1.1.0 of it
+func seed() error {
+	appService := services.NewApplicationService()
+	app, err := appService.CreateApplication(&models.ApplicationRequest{Name: "sdk-test", IsActive: true}, 1)
+	if err != nil {
+		return err
+	}
+	appID = app.AppID
+
+	key, err := appService.CreateApplicationKey(appID, &models.ApplicationKeyRequest{
+		Name:        "sdk",
+		Permissions: []string{"*"},
+		IsActive:    true,
+	}, 1)
+	if err != nil {
+		return err
+	}
+	apiKey = key.KeySecret
+
+	if err := os.MkdirAll("uploads/versions", 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile("uploads/versions/sdk-test-1.1.0", payload, 0644); err != nil {
+		return err
+	}
+
+	versionService := services.NewVersionService()
+	version, err := versionService.CreateVersion(&models.VersionRequest{
+		AppID:        appID,
+		Version:      "1.1.0",
+		Channel:      "stable",
+		Title:        "SDK test release",
+		FileURL:      "/uploads/versions/sdk-test-1.1.0",
+		FileSize:     int64(len(payload)),
+		FileChecksum: fmt.Sprintf("sha256:%x", sha256.Sum256(payload)),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = versionService.PublishVersion(version.ID)
+	return err
+}
+
+func checkUpdate(t *testing.T) (*client.Client, *client.UpdateInfo) {
+	t.Helper()
+
+	c := client.New(serverURL, appID, apiKey)
+	info, err := c.CheckUpdate(context.Background(), &client.CheckUpdateRequest{
+		CurrentVersion: "1.0.0",
+		Channel:        "stable",
+		ClientID:       "sdk-test-client",
+	})
+	if err != nil {
+		t.Fatalf("CheckUpdate() error = %v", err)
+	}
+	if !info.HasUpdate || info.LatestVersion != "1.1.0" {
+		t.Fatalf("CheckUpdate() = has_update %v, latest %q, want an update to 1.1.0", info.HasUpdate, info.LatestVersion)
+	}
+	if info.NextCheckAfter <= 0 {
+		t.Errorf("CheckUpdate() next_check_after = %d, want a polling hint", info.NextCheckAfter)
+	}
+	return c, info
+}
+
+// statusRecorder records the status code of the last response
+type statusRecorder struct {
+	status int
+}
+
+func (r *statusRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil {
+		r.status = resp.StatusCode
+	}
+	return resp, err
+}
+
+func TestCheckUpdateAndDownload(t *testing.T) {
+	c, info := checkUpdate(t)
+	ctx := context.Background()
+
+	if err := c.DownloadStarted(ctx, info.LatestVersion, "sdk-test-client"); err != nil {
+		t.Fatalf("DownloadStarted() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "app")
+	if err := c.Download(ctx, info, path); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != string(payload) {
+		t.Errorf("Download() wrote %q, want %q", got, payload)
+	}
+
+	if err := c.InstallResult(ctx, info.LatestVersion, "sdk-test-client", nil); err != nil {
+		t.Fatalf("InstallResult() error = %v", err)
+	}
+}
+
+func TestDownloadResume(t *testing.T) {
+	c, info := checkUpdate(t)
+
+	path := filepath.Join(t.TempDir(), "app")
+	if err := os.WriteFile(path+".part", payload[:10], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	transport := &statusRecorder{}
+	c.HTTPClient = &http.Client{Transport: transport}
+	if err := c.Download(context.Background(), info, path); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if transport.status != http.StatusPartialContent {
+		t.Errorf("Download() got HTTP %d, want a resumed download (HTTP 206)", transport.status)
+	}
+	if got, _ := os.ReadFile(path); string(got) != string(payload) {
+		t.Errorf("Download() wrote %q, want %q", got, payload)
+	}
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		t.Errorf("partial file still exists after download")
+	}
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	c, info := checkUpdate(t)
+	info.FileChecksum = fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("something else")))
+
+	path := filepath.Join(t.TempDir(), "app")
+	err := c.Download(context.Background(), info, path)
+	if !errors.Is(err, client.ErrChecksumMismatch) {
+		t.Fatalf("Download() error = %v, want ErrChecksumMismatch", err)
+	}
+	for _, p := range []string{path, path + ".part"} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s exists after a checksum mismatch", p)
+		}
+	}
+}
+
+func TestInvalidAPIKey(t *testing.T) {
+	c := client.New(serverURL, appID, "invalid")
+	_, err := c.CheckUpdate(context.Background(), &client.CheckUpdateRequest{
+		CurrentVersion: "1.0.0",
+		Channel:        "stable",
+		ClientID:       "sdk-test-client",
+	})
+
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 401 {
+		t.Fatalf("CheckUpdate() error = %v, want a 401 APIError", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "app")
+	if err := os.WriteFile(target, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(t.TempDir(), "new")
+	if err := os.WriteFile(src, payload, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Apply(src, target); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	stat, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Mode().Perm() != 0755 {
+		t.Errorf("Apply() mode = %v, want %v", stat.Mode().Perm(), os.FileMode(0755))
+	}
+	if got, _ := os.ReadFile(target); string(got) != string(payload) {
+		t.Errorf("Apply() wrote %q, want %q", got, payload)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Apply() left %d files in the target directory, want 1", len(entries))
+	}
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrChecksumMismatch is returned when a downloaded file doesn't match the checksum of the update
+var ErrChecksumMismatch = errors.New("vertree: checksum mismatch")
+
+// partialSuffix is appended to the destination path while a download is in progress
+const partialSuffix = ".part"
+
+// Download downloads the update to path and verifies it against info.FileChecksum.
+// The file is written to path + ".part" first: an interrupted download is resumed by a
+// later call with the same path, and path only appears once the file is verified.
+func (c *Client) Download(ctx context.Context, info *UpdateInfo, path string) error {
+	if info.DownloadURL == "" {
+		return errors.New("vertree: update has no download URL")
+	}
+	newHash, expected, err := parseChecksum(info.FileChecksum)
+	if err != nil {
+		return err
+	}
+	downloadURL, err := c.resolveURL(info.DownloadURL)
+	if err != nil {
+		return err
+	}
+
+	partial := path + partialSuffix
+	file, err := os.OpenFile(partial, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", partial, err)
+	}
+	defer file.Close()
+
+	// Hash what was downloaded before and continue after it
+	hasher := newHash()
+	offset, err := io.Copy(hasher, file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", partial, err)
+	}
+
+	if info.FileSize <= 0 || offset < info.FileSize {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		if offset > 0 {
+			req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+		}
+		if c.UserAgent != "" {
+			req.Header.Set("User-Agent", c.UserAgent)
+		}
+
+		resp, err := c.httpClient().Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to download update: %w", err)
+		}
+		defer drain(resp.Body)
+
+		switch {
+		case resp.StatusCode == http.StatusPartialContent && offset > 0 && contentRangeStart(resp) == offset:
+		case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+			// The partial file is already complete
+		case resp.StatusCode == http.StatusOK:
+			// The server sent the whole file, start over
+			if err := file.Truncate(0); err != nil {
+				return fmt.Errorf("failed to truncate %s: %w", partial, err)
+			}
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return fmt.Errorf("failed to seek %s: %w", partial, err)
+			}
+			hasher.Reset()
+		default:
+			return &APIError{StatusCode: resp.StatusCode, Message: "failed to download update"}
+		}
+
+		if resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+			if _, err := io.Copy(io.MultiWriter(file, hasher), resp.Body); err != nil {
+				return fmt.Errorf("failed to download update: %w", err)
+			}
+		}
+	}
+
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", partial, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", partial, err)
+	}
+
+	// A corrupt partial file can't be resumed, so remove it to download again from scratch
+	if actual := hex.EncodeToString(hasher.Sum(nil)); actual != expected {
+		os.Remove(partial)
+		return fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, expected, actual)
+	}
+
+	if err := os.Rename(partial, path); err != nil {
+		return fmt.Errorf("failed to move download to %s: %w", path, err)
+	}
+	return nil
+}
+
+// parseChecksum parses a file checksum, "sha256:<hex>", "sha512:<hex>" or a bare hex
+// digest, into its hash function and lowercase hex digest
+func parseChecksum(checksum string) (func() hash.Hash, string, error) {
+	algorithm, digest, found := strings.Cut(strings.ToLower(strings.TrimSpace(checksum)), ":")
+	if !found {
+		algorithm, digest = "", algorithm
+	}
+	if digest == "" {
+		return nil, "", errors.New("vertree: update has no checksum")
+	}
+
+	decoded, err := hex.DecodeString(digest)
+	if err != nil {
+		return nil, "", fmt.Errorf("vertree: invalid checksum %q", checksum)
+	}
+
+	switch {
+	case len(decoded) == sha256.Size && (algorithm == "" || algorithm == "sha256"):
+		return sha256.New, digest, nil
+	case len(decoded) == sha512.Size && (algorithm == "" || algorithm == "sha512"):
+		return sha512.New, digest, nil
+	default:
+		return nil, "", fmt.Errorf("vertree: unsupported checksum %q", checksum)
+	}
+}
+
+// contentRangeStart returns the first byte position of a "bytes <start>-<end>/<size>" Content-Range
+func contentRangeStart(resp *http.Response) int64 {
+	value := strings.TrimPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	start, _, _ := strings.Cut(value, "-")
+	position, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return position
+}